@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+// cacheStatus describes the holiday cache for --cache-status.
+type cacheStatus struct {
+	Path     string          `json:"path"`
+	Exists   bool            `json:"exists"`
+	Modified string          `json:"modified,omitempty"` // RFC 3339
+	AgeDays  int             `json:"age_days"`
+	Version  int             `json:"format_version"` // 0 for caches written before versioning
+	Valid    bool            `json:"valid"`
+	Years    *cacheYearRange `json:"years,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+type cacheYearRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// inspectCache reports on the cache file at path as of now. A cache that
+// exists but cannot be read is not valid, matching how the calendar treats
+// it.
+func inspectCache(path string, now time.Time) cacheStatus {
+	status := cacheStatus{Path: path}
+	info, err := os.Stat(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			status.Error = err.Error()
+		}
+		return status
+	}
+	status.Exists = true
+	status.Modified = info.ModTime().Format(time.RFC3339)
+	status.AgeDays = int(now.Sub(info.ModTime()).Hours() / 24)
+
+	valid, err := holidays.IsCacheValid(path)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Version, _ = holidays.CacheVersion(path)
+	data, _, err := holidays.Load(path)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Valid = valid
+	if coverage := holidays.Coverage(data); coverage != nil {
+		status.Years = &cacheYearRange{Min: coverage.MinYear, Max: coverage.MaxYear}
+	}
+	return status
+}
+
+// runCacheStatus prints the state of the holiday cache as text or, with
+// jsonOutput, a single JSON object. It returns the process exit code: 0 when
+// the cache is valid, 1 when it is missing, stale or unreadable and 2 when
+// its location cannot be determined.
+func runCacheStatus(jsonOutput bool) int {
+	path, err := holidays.GetCachePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		return 2
+	}
+	status := inspectCache(path, time.Now())
+	if jsonOutput {
+		payload, _ := json.Marshal(status)
+		fmt.Println(string(payload))
+	} else {
+		fmt.Printf("缓存文件：%s\n", status.Path)
+		if status.Exists {
+			fmt.Printf("修改时间：%s（%d 天前）\n", status.Modified, status.AgeDays)
+			fmt.Printf("格式版本：%d（当前 %d）\n", status.Version, holidays.CacheFormatVersion)
+		} else {
+			fmt.Println("修改时间：文件不存在")
+		}
+		if status.Years != nil {
+			fmt.Printf("数据年份：%d-%d\n", status.Years.Min, status.Years.Max)
+		}
+		if status.Error != "" {
+			fmt.Printf("错误：%s\n", status.Error)
+		}
+		if status.Valid {
+			fmt.Println("状态：有效")
+		} else {
+			fmt.Println("状态：缺失、已过期（超过 6 个月未更新）或格式不受支持，运行 lucal -u 更新")
+		}
+	}
+	if !status.Valid {
+		return 1
+	}
+	return 0
+}
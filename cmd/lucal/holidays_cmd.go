@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lululau/lucal/internal/holidays"
+	"github.com/lululau/lucal/internal/render"
+)
+
+// runHolidaysCommand implements `lucal holidays [--since D] [--until D]
+// [--only-holidays|--only-workdays] [--date-format F]`, printing one "date name" line per entry.
+// It returns the process exit code.
+func runHolidaysCommand(args []string, data map[string]map[string]*holidays.HolidayEntry) int {
+	fs := flag.NewFlagSet("holidays", flag.ContinueOnError)
+	now := time.Now()
+	since := fs.String("since", fmt.Sprintf("%d-01-01", now.Year()), "起始日期 (YYYY-MM-DD)")
+	until := fs.String("until", fmt.Sprintf("%d-12-31", now.Year()), "结束日期 (YYYY-MM-DD)")
+	onlyHolidays := fs.Bool("only-holidays", false, "只列出法定节假日")
+	onlyWorkdays := fs.Bool("only-workdays", false, "只列出调休上班日")
+	dateFormat := fs.String("date-format", "iso", "输出日期的格式：iso、slash、compact 或 Go 时间布局")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: lucal holidays [--since YYYY-MM-DD] [--until YYYY-MM-DD] [--only-holidays|--only-workdays] [--date-format F]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", *since, time.Local)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法将 %q 解析为日期，格式应为 YYYY-MM-DD\n", *since)
+		return 2
+	}
+	to, err := time.ParseInLocation("2006-01-02", *until, time.Local)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法将 %q 解析为日期，格式应为 YYYY-MM-DD\n", *until)
+		return 2
+	}
+	if from.After(to) {
+		fmt.Fprintf(os.Stderr, "错误: --since (%s) 不能晚于 --until (%s)\n", *since, *until)
+		return 2
+	}
+	layout, err := render.ParseDateFormat(*dateFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		return 2
+	}
+	if *onlyHolidays && *onlyWorkdays {
+		fmt.Fprintln(os.Stderr, "错误: --only-holidays 与 --only-workdays 不能同时使用")
+		return 2
+	}
+
+	coverage := holidays.Coverage(data)
+	switch {
+	case coverage == nil:
+		fmt.Fprintln(os.Stderr, "警告: 未加载节假日数据，运行 lucal -u 获取最新数据")
+	case from.Year() < coverage.MinYear || to.Year() > coverage.MaxYear:
+		fmt.Fprintf(os.Stderr, "警告: 节假日数据仅覆盖 %d-%d 年，超出部分不会列出\n", coverage.MinYear, coverage.MaxYear)
+	}
+
+	for _, h := range holidays.GetHolidaysInRange(data, from, to) {
+		if (*onlyHolidays && !h.IsHoliday) || (*onlyWorkdays && h.IsHoliday) {
+			continue
+		}
+		kind := "休"
+		if !h.IsHoliday {
+			kind = "班"
+		}
+		name := h.Name
+		if adjustment := h.Adjustment(); adjustment != "" {
+			name += "（" + adjustment + "）"
+		}
+		fmt.Printf("%s %s %s\n", h.Date.Format(layout), kind, name)
+	}
+	return 0
+}
@@ -1,61 +1,308 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+
+	"github.com/lululau/lucal/internal/almanac"
 	"github.com/lululau/lucal/internal/calendar"
 	"github.com/lululau/lucal/internal/holidays"
+	"github.com/lululau/lucal/internal/notes"
 	"github.com/lululau/lucal/internal/render"
+	"github.com/lululau/lucal/internal/textwidth"
 	"github.com/lululau/lucal/internal/tui"
 )
 
 var (
-	yearFlag      = flag.Bool("y", false, "显示全年日历")
-	plain         = flag.Bool("n", false, "直接渲染并退出（非交互模式）")
-	updateHolidays = flag.Bool("u", false, "下载最新的节假日数据")
+	yearFlag           = flag.Bool("y", false, "显示全年日历")
+	plain              = flag.Bool("n", false, "直接渲染并退出（非交互模式）")
+	interactive        = flag.Bool("interactive", false, "与 -y 或 --from/--to 一起使用：以交互界面显示全年，而不是渲染后退出（--from/--to 时用 J/K 在范围内逐年翻页）")
+	updateHolidays     = flag.Bool("u", false, "下载最新的节假日数据")
 	updateHolidaysLong = flag.Bool("update-holidays", false, "下载最新的节假日数据")
-	holidaysFile  = flag.String("h", "", "指定节假日数据文件路径（用于调试）")
-	holidaysFileLong = flag.String("holidays-file", "", "指定节假日数据文件路径（用于调试）")
-	noColor       = flag.Bool("N", false, "禁用所有颜色输出")
-	noColorLong   = flag.Bool("no-color", false, "禁用所有颜色输出")
+	cacheStatusFlag    = flag.Bool("cache-status", false, "显示节假日缓存的路径、修改时间、年份范围和是否有效，然后退出；缓存缺失或过期时退出码为 1（--format=json 时输出 JSON）")
+	strict             = flag.Bool("strict", false, "把节假日数据的警告（日期无法解析、日期与键值不一致、重复条目、文件为空）和加载失败（含 URL 返回 HTML）视为错误，以退出码 1 结束")
+	dryRun             = flag.Bool("dry-run", false, "与 -u 一起使用：下载并与当前缓存比较，但不替换缓存")
+	holidaysFile       = flag.String("h", "", "指定节假日数据文件路径或 http(s) URL（用于调试，URL 不写入缓存）")
+	holidaysFileLong   = flag.String("holidays-file", "", "指定节假日数据文件路径或 http(s) URL（用于调试，URL 不写入缓存）")
+	noColor            = flag.Bool("N", false, "禁用所有颜色输出")
+	noColorLong        = flag.Bool("no-color", false, "禁用所有颜色输出")
+	noBorder           = flag.Bool("no-border", false, "不绘制月份外框")
+	firstDay           = flag.String("first-day", "sun", "每周的第一天：sun、mon、monday 等名称，或数字 0-7（0 和 7 均为周日，1 为周一）")
+	weekendDays        = flag.String("weekend", "sat,sun", "周末（休息日）列表，以逗号分隔，如 fri,sat；节假日数据仍优先生效")
+	yearColumns        = flag.Int("year-columns", 0, "年视图每行显示的月份数 (1-12)，默认按终端宽度自动选择")
+	gutter             = flag.Int("gutter", render.DefaultGutter, "多个月份并排显示时月份之间的空格数 (>= 0)")
+	fiscalStart        = flag.Int("fiscal-start", 0, "与 -y 或 --from/--to 一起使用：财年起始月份 (1-12)，年视图显示从该月起的 12 个月")
+	ambiguousWidth     = flag.Int("ambiguous-width", 0, "East Asian Ambiguous 字符（如 ─ ·）占用的列数：1 或 2，默认读取 $LUCAL_AMBIGUOUS_WIDTH，否则为 1")
+	widthMode          = flag.String("width-mode", textwidth.ModeGBK, "计算字符宽度的方式：gbk（按 GBK 编码，默认）、unicode（按 Unicode 东亚宽度）、ambiguous1 或 ambiguous2（unicode，且 Ambiguous 字符固定为 1 或 2 列）；用于排查对不齐的终端")
+	fillScreen         = flag.Bool("fill", false, "交互模式的月视图从当前月起连续显示多个月份，铺满终端高度（j/k 逐月滚动）")
+	jumpStep           = flag.Int("jump-step", tui.DefaultJumpStep, "交互模式下 Ctrl-F/Ctrl-B 前进/后退的月数")
+	fromYear           = flag.Int("from", 0, "与 --to 一起使用：依次显示从该年到 --to 的每一年")
+	toYear             = flag.Int("to", 0, "与 --from 一起使用：年份范围的最后一年")
+	themeFile          = flag.String("theme", "", "指定配色文件路径（JSON，如 {\"weekend\": \"#94A3B8\", \"sunday\": \"#EF4444\"}）")
+	holidayRules       = flag.String("holiday-rules", "", "指定节假日分类配色文件路径（JSON 数组，如 [{\"min_wage\": 3, \"color\": \"#EF4444\", \"label\": \"法定节假日\"}]，按 min_wage 或名称正则 name 匹配，先匹配的规则生效）")
+	holidayColor       = flag.String("holiday-color", "", "节假日的颜色 (#RRGGBB)，覆盖 --theme 和默认配色")
+	workdayColor       = flag.String("workday-color", "", "调休上班日的颜色 (#RRGGBB)，覆盖 --theme 和默认配色")
+	todayColor         = flag.String("today-color", "", "今天的颜色 (#RRGGBB)，覆盖 --theme 和默认配色")
+	weekendColor       = flag.String("weekend-color", "", "周末的颜色 (#RRGGBB)，覆盖 --theme 和默认配色")
+	showAdjacent       = flag.Bool("show-adjacent", false, "以灰色显示上月末和下月初的日期，填满日历网格")
+	noAdjacentLunar    = flag.Bool("no-lunar-for-adjacent", false, "与 --show-adjacent 一起使用：相邻月份的日期只显示公历日期，不显示农历")
+	bilingualHeader    = flag.String("bilingual-header", "", "在中文星期表头下再加一行：en（Su Mo Tu）或 pinyin（ri yi er）")
+	dayNumeral         = flag.String("day-numeral", render.DayNumeralArabic, "日期数字的写法：arabic（1 2 3）、chinese（一 二 三）或 fullwidth（１ ２ ３）")
+	dayAlign           = flag.String("day-align", render.DayAlignLeft, "日期数字在格子中的位置：left（按 %2d 补空格，如 \" 1\"）或 center（在农历上方居中）")
+	lunarPosition      = flag.String("lunar-position", render.LunarBelow, "农历显示位置：below（日期下方）、above（日期上方）、inline（同一行，如 18 初九）或 none（不显示）")
+	sixWeeksMode       = flag.String("six-weeks", render.SixWeeksAuto, "把每个月补足 6 周的高度：auto（多个月份时）、on 或 off")
+	dayOfYear          = flag.Bool("day-of-year", false, "在农历下方再显示一行当天是一年中的第几天（如 #332）")
+	showHolidaySummary = flag.Bool("holiday-summary", false, "在每个月下方用一行列出当月的节假日和调休日")
+	holidaysOnly       = flag.Bool("holidays-only", false, "以灰色显示普通工作日，突出节假日、调休和周末")
+	notify             = flag.Bool("notify", false, "今天是法定节假日时在标准错误输出一句祝福（如 今天是 国庆节，假期愉快！）")
+	notifyCommand      = flag.String("notify-command", "", "今天是法定节假日时改为运行此命令（隐含 --notify），按空白分词，可用 {message} {name} {date}，如 \"notify-send lucal {message}\"")
+	quiet              = flag.Bool("Q", false, "只输出日历本身（或所选格式），不输出摘要、颜色图例、更新提醒和提示信息；警告仍输出到标准错误")
+	quietLong          = flag.Bool("quiet", false, "只输出日历本身（或所选格式），不输出摘要、颜色图例、更新提醒和提示信息；警告仍输出到标准错误")
+	dense              = flag.Bool("dense", false, "去掉月份表头下方和各周之间的空行，让每个月占用更少的行")
+	pentad             = flag.Bool("pentad", false, "在日历下方显示今天所在的七十二候（如 今日物候：蚯蚓结）")
+	noUpdateHint       = flag.Bool("no-update-hint", false, "不显示节假日数据缺失或过期的更新提醒")
+	hyperlinks         = flag.String("hyperlinks", "", "把日期做成终端超链接 (OSC 8)，参数为 URL 模板，可用 {date} {year} {month} {day}；终端不支持时忽略")
+	notesFile          = flag.String("notes", "", "指定个人备注文件路径（JSON，键为 YYYY-MM-DD）")
+	markDates          = flag.String("mark", "", "用醒目的颜色（主题键 marked）标出指定日期，以逗号分隔，如 2025-11-11,2025-11-25；不在显示范围内的日期会被忽略并给出警告")
+	almanacFile        = flag.String("almanac", "", "指定黄历宜忌数据文件路径（JSON，键为农历日期，如 乙巳年九月廿二）")
+	format             = flag.String("format", render.FormatText, "输出格式: text、json、cal、mini、svg 或 png（单个月份的 SVG 或 PNG 图片，可配合 --output 保存；json 时错误信息也以 JSON 输出到标准错误）")
+	jsonPretty         = flag.Bool("json-pretty", true, "--format=json 时输出缩进格式；设为 false 时每个月输出一行 JSON（NDJSON，便于流式处理）")
+	watch              = flag.Bool("watch", false, "不进入交互界面，每隔 --watch-interval 清屏并重新渲染（终端尺寸变化时立即重绘），Ctrl+C 退出")
+	watchInterval      = flag.Duration("watch-interval", time.Minute, "--watch 的重绘间隔，如 30s、5m")
+	outputFile         = flag.String("output", "", "把渲染结果写入指定文件（会自动创建上级目录），隐含 -n")
+	isoWeek            = flag.Bool("iso-week", false, "--format=json 中为每一天加上 ISO 8601 周数 (iso_week) 及其所属年份 (iso_week_year)")
+	dateFormat         = flag.String("date-format", "iso", "--format=json 中日期的格式：iso (2006-01-02)、slash (2006/01/02)、compact (20060102) 或 Go 时间布局")
+	calGrid            = flag.Bool("plain-ascii-grid", false, "模仿经典 Unix cal 的输出（等同于 --format=cal）")
+	mini               = flag.Bool("mini", false, "输出 22 列宽的迷你月历，今天用 [] 标出，适合嵌入提示符（等同于 --format=mini）")
+	printWidth         = flag.Bool("print-width", false, "调试对齐问题：在标准错误输出每个月份、整个网格和终端的宽度，以及一条同宽的标尺")
+	debug              = flag.Bool("debug", false, "输出调试日志到标准错误")
+	ageOf              = flag.String("age", "", "按出生日期 (YYYY-MM-DD) 计算今天的周岁和虚岁")
+	lunarNewYear       = flag.Int("lunar-new-year", 0, "显示指定公历年份的春节（正月初一）日期")
+	lunarMonths        = flag.Int("lunar-months", 0, "列出指定公历年份中每个农历月初一的日期")
+	isWorkday          = flag.String("is-workday", "", "判断指定日期 (YYYY-MM-DD) 是否为工作日，是则退出码为 0，否则为 1")
+	timeZone           = flag.String("tz", "", "按指定时区（IANA 名称，如 Asia/Shanghai）判断今天和当前月份，默认使用本机时区")
 )
 
+// location is the time zone of --tz; "today" and the current month are read
+// there.
+var location = time.Local
+
+// clock reports the current time; "today" and the current month are read
+// from it. Tests replace it to pin the date.
+var clock = time.Now
+
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "用法: %s [选项] [year] [month]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "      %s [选项] holidays [--since YYYY-MM-DD] [--until YYYY-MM-DD] [--only-holidays|--only-workdays]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "      %s [选项] validate-holidays [--strict] [--format text|json] <文件或 URL>\n", os.Args[0])
 		fmt.Fprintf(flag.CommandLine.Output(), `
   无参数      展示当前月份
   -y          展示当前年份
   9           展示当年9月份
   1983        展示1983年
   2012 12     展示2012年12月
+  Nov/十一月  展示当年11月份
+  +1          展示下个月（相对当前月份偏移，-y 时按年偏移）
+  -- -2       展示两个月前（负数偏移需放在 -- 之后，以免被当作选项）
   -y 9        展示公元9年的全年
 
+环境变量 LUCAL_ARGS 中以空白分隔的选项（如 "-N --first-day=mon"）会放在命令行参数之前，
+作为可被命令行覆盖的默认值；不支持引号，也不要放入年月等位置参数。
+
 选项:
 `)
 		flag.PrintDefaults()
 	}
-	flag.Parse()
+	// ExitOnError: Parse exits on its own.
+	_ = flag.CommandLine.Parse(withEnvArgs(os.Getenv("LUCAL_ARGS"), os.Args[1:]))
+	setupLogging(*debug)
+
+	if *calGrid {
+		*format = render.FormatCal
+	}
+	if *mini {
+		*format = render.FormatMini
+	}
+	switch *format {
+	case render.FormatText, render.FormatJSON, render.FormatCal, render.FormatMini, render.FormatSVG, render.FormatPNG:
+	default:
+		fail(argumentError{fmt.Errorf("不支持的输出格式 %q，可选 text、json、cal、mini、svg 或 png", *format)})
+	}
+	if *format == render.FormatPNG && *outputFile == "" && isatty.IsTerminal(os.Stdout.Fd()) {
+		fail(argumentError{errors.New("--format=png 需要用 --output 指定文件，或把标准输出重定向到文件")})
+	}
+	dateLayout, dateErr := render.ParseDateFormat(*dateFormat)
+	if dateErr != nil {
+		fail(argumentError{dateErr})
+	}
 
 	// Set no-color flag if specified
 	if *noColor || *noColorLong {
 		render.SetNoColor(true)
 		tui.SetNoColor(true)
 	}
+	if *noBorder {
+		render.SetNoBorder(true)
+	}
+	if *showAdjacent {
+		render.SetShowAdjacent(true)
+	}
+	if *noAdjacentLunar {
+		render.SetNoLunarForAdjacent(true)
+	}
+	if err := render.SetLunarPosition(*lunarPosition); err != nil {
+		fail(argumentError{err})
+	}
+	if err := render.SetDayNumeral(*dayNumeral); err != nil {
+		fail(argumentError{err})
+	}
+	if err := render.SetDayAlign(*dayAlign); err != nil {
+		fail(argumentError{err})
+	}
+	if err := render.SetBilingualHeader(*bilingualHeader); err != nil {
+		fail(argumentError{err})
+	}
+	if err := render.SetSixWeeks(*sixWeeksMode); err != nil {
+		fail(argumentError{err})
+	}
+	if *dayOfYear {
+		render.SetDayOfYear(true)
+	}
+	if *showHolidaySummary {
+		render.SetHolidaySummary(true)
+	}
+	if *holidaysOnly {
+		render.SetHolidaysOnly(true)
+	}
+	if *dense {
+		render.SetDense(true)
+	}
+	if *pentad {
+		render.SetPentad(true)
+	}
+	if *noUpdateHint {
+		render.SetNoUpdateHint(true)
+	}
+	if *printWidth {
+		render.SetPrintWidth(os.Stderr)
+	}
+	if *hyperlinks != "" {
+		if render.HyperlinksSupported(os.Stdout.Fd()) {
+			render.SetHyperlinks(*hyperlinks)
+		} else {
+			slog.Debug("hyperlinks disabled, terminal support not detected")
+		}
+	}
+	if *themeFile != "" {
+		theme, err := render.LoadTheme(*themeFile)
+		if err == nil {
+			err = render.ApplyTheme(theme)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 无法加载配色文件 %s: %v\n", *themeFile, err)
+		}
+	}
+	if *holidayRules != "" {
+		rules, err := render.LoadHolidayRules(*holidayRules)
+		if err == nil {
+			err = render.SetHolidayRules(rules)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 无法加载节假日分类文件 %s: %v\n", *holidayRules, err)
+		}
+	}
+	for _, c := range []struct{ name, key, value string }{
+		{"holiday-color", "holiday", *holidayColor},
+		{"workday-color", "workday", *workdayColor},
+		{"today-color", "today", *todayColor},
+		{"weekend-color", "weekend", *weekendColor},
+	} {
+		if c.value == "" {
+			continue
+		}
+		if err := render.SetColors(render.Theme{c.key: c.value}); err != nil {
+			fail(argumentError{fmt.Errorf("--%s: %w", c.name, err)})
+		}
+	}
+	if *yearColumns != 0 {
+		if *yearColumns < 1 || *yearColumns > 12 {
+			fail(argumentError{fmt.Errorf("--year-columns 需要在 1-12 之间 (收到 %d)", *yearColumns)})
+		}
+		render.SetYearColumns(*yearColumns)
+	}
+	if *gutter < 0 {
+		fail(argumentError{fmt.Errorf("--gutter 不能小于 0 (收到 %d)", *gutter)})
+	}
+	render.SetGutter(*gutter)
+	if *fiscalStart != 0 && (*fiscalStart < 1 || *fiscalStart > 12) {
+		fail(argumentError{fmt.Errorf("--fiscal-start 需要在 1-12 之间 (收到 %d)", *fiscalStart)})
+	}
+	ambiguous, ambiguousErr := parseAmbiguousWidth(*ambiguousWidth, os.Getenv("LUCAL_AMBIGUOUS_WIDTH"))
+	if ambiguousErr != nil {
+		fail(argumentError{ambiguousErr})
+	}
+	textwidth.SetAmbiguousWidth(ambiguous)
+	if err := textwidth.SetMode(*widthMode); err != nil {
+		fail(argumentError{err})
+	}
+	if *jumpStep < 1 {
+		fail(argumentError{fmt.Errorf("--jump-step 需要大于 0 (收到 %d)", *jumpStep)})
+	}
+	loc, tzErr := parseTimeZone(*timeZone)
+	if tzErr != nil {
+		fail(argumentError{tzErr})
+	}
+	location = loc
+	tui.SetJumpStep(*jumpStep)
+	tui.SetFiscalStart(*fiscalStart)
+	tui.SetFillScreen(*fillScreen)
+
+	if *ageOf != "" {
+		os.Exit(runAge(*ageOf, clock().In(location)))
+	}
+	if *lunarMonths != 0 {
+		os.Exit(runLunarMonths(*lunarMonths))
+	}
+	if *lunarNewYear != 0 {
+		os.Exit(runLunarNewYear(*lunarNewYear))
+	}
+
+	if *cacheStatusFlag {
+		os.Exit(runCacheStatus(*format == render.FormatJSON))
+	}
+	if flag.Arg(0) == "validate-holidays" {
+		os.Exit(runValidateHolidays(flag.Args()[1:], *strict, *format))
+	}
 
 	// Handle update holidays flag
 	if *updateHolidays || *updateHolidaysLong {
-		if err := holidays.DownloadHolidays(); err != nil {
-			fmt.Fprintln(os.Stderr, "错误:", err)
-			os.Exit(1)
+		download := holidays.DownloadHolidays
+		if *dryRun {
+			download = holidays.DryRunDownload
+		}
+		if err := download(); err != nil {
+			fail(err)
 		}
 		return
 	}
+	if *dryRun {
+		fail(argumentError{errors.New("--dry-run 需要与 -u 一起使用")})
+	}
 
 	// Load holiday data
 	var holidayData map[string]map[string]*holidays.HolidayEntry
@@ -69,63 +316,372 @@ func main() {
 
 	if holidayFilePath != "" {
 		// Load from specified file
-		holidayData, err = holidays.LoadFromFile(holidayFilePath)
+		slog.Debug("loading holidays from file", "path", holidayFilePath)
+		var warnings []holidays.Warning
+		if holidays.IsURL(holidayFilePath) {
+			holidayData, warnings, err = holidays.LoadFromURL(holidayFilePath)
+		} else {
+			holidayData, warnings, err = holidays.Load(holidayFilePath)
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "警告: 无法加载节假日文件 %s: %v\n", holidayFilePath, err)
+			if *strict {
+				fail(fmt.Errorf("无法加载节假日数据 %s: %w", holidayFilePath, err))
+			}
+			fmt.Fprintf(os.Stderr, "警告: 无法加载节假日数据 %s: %v\n", holidayFilePath, err)
 		} else {
 			cacheValid = true
 		}
+		if *strict && len(warnings) > 0 {
+			fail(strictError{holidayFilePath, warnings})
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "警告: 节假日文件 %s: %s\n", holidayFilePath, w)
+		}
 	} else {
 		// Try to load from cache
 		cachePath, cacheErr := holidays.GetCachePath()
 		if cacheErr == nil {
 			valid, validErr := holidays.IsCacheValid(cachePath)
+			logCacheState(cachePath, valid, validErr)
 			if validErr == nil {
 				cacheValid = valid
 				if valid {
-					holidayData, err = holidays.LoadFromCache()
+					var warnings []holidays.Warning
+					holidayData, warnings, err = holidays.Load(cachePath)
 					if err != nil {
 						// Cache file exists but can't be read, mark as invalid
+						slog.Debug("holiday cache unreadable", "path", cachePath, "err", err)
 						cacheValid = false
+					} else if *strict && len(warnings) > 0 {
+						fail(strictError{cachePath, warnings})
 					}
 				}
 			}
+		} else {
+			slog.Debug("holiday cache path unavailable", "err", cacheErr)
 		}
 	}
 
+	weekend, err := parseWeekend(*weekendDays)
+	if err != nil {
+		fail(argumentError{err})
+	}
+	if *isWorkday != "" {
+		os.Exit(runIsWorkday(*isWorkday, holidayData, weekend))
+	}
+	if flag.Arg(0) == "holidays" {
+		os.Exit(runHolidaysCommand(flag.Args()[1:], holidayData))
+	}
+
 	req, err := parseRequest(*yearFlag, flag.Args())
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "错误:", err)
-		os.Exit(1)
+		fail(argumentError{err})
+	}
+	if *fromYear != 0 || *toYear != 0 {
+		if flag.NArg() > 0 {
+			fail(argumentError{errors.New("--from/--to 不能与年月参数同时使用")})
+		}
+		if req, err = parseYearRange(*fromYear, *toYear); err != nil {
+			fail(argumentError{err})
+		}
+	}
+	if *fiscalStart != 0 && req.Mode != calendar.ModeYear {
+		fail(argumentError{errors.New("--fiscal-start 需要与 -y 或 --from/--to 一起使用")})
+	}
+	marks, err := parseMarks(*markDates)
+	if err != nil {
+		fail(argumentError{err})
+	}
+	for _, mark := range marksOutside(marks, req, *toYear, *fiscalStart) {
+		fmt.Fprintf(os.Stderr, "警告: 标记日期 %s 不在显示范围内，已忽略\n", mark.Format("2006-01-02"))
 	}
 
-	// Create service with holiday data
-	service := calendar.NewService()
+	// Create service with holiday data and personal notes
+	weekStart, err := parseWeekday(*firstDay)
+	if err != nil {
+		fail(argumentError{err})
+	}
+	serviceOpts := []calendar.Option{calendar.WithWeekStart(weekStart), calendar.WithWeekend(weekend...), calendar.WithLocation(location)}
 	if holidayData != nil {
-		service = calendar.NewService(calendar.WithHolidays(holidayData))
+		serviceOpts = append(serviceOpts, calendar.WithHolidays(holidayData))
+	}
+	if *notesFile != "" {
+		noteData, err := notes.LoadFromFile(*notesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 无法加载备注文件 %s: %v\n", *notesFile, err)
+		} else {
+			serviceOpts = append(serviceOpts, calendar.WithNotes(noteData))
+		}
+	}
+	if len(marks) > 0 {
+		serviceOpts = append(serviceOpts, calendar.WithMarks(marks...))
+	}
+	if *almanacFile != "" {
+		almanacData, err := almanac.LoadFromFile(*almanacFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 无法加载黄历文件 %s: %v\n", *almanacFile, err)
+		} else {
+			serviceOpts = append(serviceOpts, calendar.WithAlmanac(almanacData))
+		}
+	}
+	service := calendar.NewService(serviceOpts...)
+	if *notify || *notifyCommand != "" {
+		notifyHoliday(service.Today(), *notifyCommand)
+	}
+
+	plainOpts := render.PlainOptions{
+		Service:           service,
+		Request:           req,
+		HolidayCacheValid: cacheValid,
+		Format:            *format,
+		CompactJSON:       !*jsonPretty,
+		ToYear:            *toYear,
+		DateFormat:        dateLayout,
+		FiscalStart:       *fiscalStart,
+		ISOWeek:           *isoWeek,
+		Quiet:             *quiet || *quietLong,
+	}
+	if *watch {
+		if *watchInterval <= 0 {
+			fail(argumentError{fmt.Errorf("--watch-interval 需要大于 0 (收到 %s)", *watchInterval)})
+		}
+		// Without year/month arguments the view follows the current month.
+		follow := flag.NArg() == 0 && *fromYear == 0
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, append([]os.Signal{os.Interrupt, syscall.SIGTERM}, resizeSignals...)...)
+		os.Exit(runWatch(os.Stdout, func(w io.Writer) error {
+			opts := plainOpts
+			opts.Writer = w
+			if follow {
+				opts.Request, _ = parseRequest(*yearFlag, nil)
+			}
+			return render.RunPlain(opts)
+		}, *watchInterval, signals))
 	}
 
-	nonInteractive := *plain || req.Mode == calendar.ModeYear
+	// Data fetched from a URL is meant for one-off runs; don't start the TUI
+	// when the output is piped.
+	urlSource := holidays.IsURL(holidayFilePath) && !isatty.IsTerminal(os.Stdout.Fd())
+	// The year view renders once unless --interactive asks for the TUI.
+	yearOnce := req.Mode == calendar.ModeYear && !*interactive
+	nonInteractive := *plain || urlSource || yearOnce || *format != render.FormatText || *outputFile != ""
 	if nonInteractive {
-		if err := render.RunPlain(render.PlainOptions{
-			Service:          service,
-			Request:          req,
-			HolidayCacheValid: cacheValid,
-		}); err != nil {
-			fmt.Fprintln(os.Stderr, "错误:", err)
-			os.Exit(1)
+		var out io.Writer = os.Stdout
+		var file *os.File
+		if *outputFile != "" {
+			if file, err = createOutput(*outputFile); err != nil {
+				fail(err)
+			}
+			out = file
+		}
+		plainOpts.Writer = out
+		err := render.RunPlain(plainOpts)
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			fail(err)
+		}
+		if file != nil && !plainOpts.Quiet {
+			fmt.Fprintf(os.Stderr, "已写入 %s\n", file.Name())
 		}
 		return
 	}
 
+	if *toYear != 0 {
+		tui.SetYearRange(*fromYear, *toYear)
+	}
 	if err := tui.Run(service, req, cacheValid); err != nil {
+		fail(err)
+	}
+}
+
+// withEnvArgs prepends the whitespace-separated tokens of env ($LUCAL_ARGS)
+// to args, so they act as defaults that flags given later on the command
+// line override. Quoting is not supported.
+func withEnvArgs(env string, args []string) []string {
+	tokens := strings.Fields(env)
+	if len(tokens) == 0 {
+		return args
+	}
+	return append(tokens, args...)
+}
+
+// createOutput creates (or truncates) the --output file, making its parent
+// directories first.
+func createOutput(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("无法创建输出目录: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法创建输出文件: %w", err)
+	}
+	return file, nil
+}
+
+// argumentError marks errors caused by malformed command-line arguments.
+type argumentError struct {
+	err error
+}
+
+func (e argumentError) Error() string { return e.err.Error() }
+func (e argumentError) Unwrap() error { return e.err }
+
+// strictError promotes the warnings from loading the holiday data at source
+// to a fatal error under --strict.
+type strictError struct {
+	source   string
+	warnings []holidays.Warning
+}
+
+func (e strictError) Error() string {
+	problems := make([]string, len(e.warnings))
+	for i, w := range e.warnings {
+		problems[i] = w.String()
+	}
+	return fmt.Sprintf("节假日文件 %s 有 %d 个问题 (--strict): %s", e.source, len(e.warnings), strings.Join(problems, "; "))
+}
+
+// fail reports err on stderr and exits with status 1. With --format=json the
+// report is a single JSON object carrying a stable code so wrapping tools can
+// parse failures uniformly.
+func fail(err error) {
+	if *format == render.FormatJSON {
+		payload, _ := json.Marshal(struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}{err.Error(), errorCode(err)})
+		fmt.Fprintln(os.Stderr, string(payload))
+	} else {
 		fmt.Fprintln(os.Stderr, "错误:", err)
-		os.Exit(1)
 	}
+	os.Exit(1)
+}
+
+// errorCode maps known errors to stable machine-readable codes.
+func errorCode(err error) string {
+	var argErr argumentError
+	switch {
+	case errors.Is(err, calendar.ErrYearOutOfRange):
+		return "year_out_of_range"
+	case errors.Is(err, calendar.ErrInvalidMonth):
+		return "invalid_month"
+	case errors.Is(err, holidays.ErrObjectShape), errors.As(err, new(*holidays.HolidayParseError)):
+		return "invalid_holiday_file"
+	case errors.As(err, new(strictError)):
+		return "holiday_warning"
+	case errors.As(err, &argErr):
+		return "invalid_argument"
+	default:
+		return "error"
+	}
+}
+
+// setupLogging routes slog output to stderr when debugging and discards it
+// otherwise, so stdout only ever carries the calendar.
+func setupLogging(enabled bool) {
+	if !enabled {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return
+	}
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+	slog.SetDefault(slog.New(handler))
+	slog.Debug("terminal", "color_profile", lipgloss.ColorProfile().Name(), "no_color", *noColor || *noColorLong)
+}
+
+func logCacheState(cachePath string, valid bool, err error) {
+	if err != nil {
+		slog.Debug("holiday cache check failed", "path", cachePath, "err", err)
+		return
+	}
+	info, statErr := os.Stat(cachePath)
+	if statErr != nil {
+		slog.Debug("holiday cache missing", "path", cachePath)
+		return
+	}
+	age := time.Since(info.ModTime()).Round(time.Hour)
+	slog.Debug("holiday cache", "path", cachePath, "valid", valid, "modified", info.ModTime().Format(time.RFC3339), "age", age)
+}
+
+// runAge prints the 周岁 and 虚岁 of someone born on value as of now and
+// returns the process exit code: 0 on success, 2 when the date is invalid.
+func runAge(value string, now time.Time) int {
+	birth, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法将 %q 解析为日期，格式应为 YYYY-MM-DD\n", value)
+		return 2
+	}
+	if birth.After(now) {
+		fmt.Fprintf(os.Stderr, "错误: 出生日期 %s 晚于今天\n", value)
+		return 2
+	}
+	if birth.Year() < calendar.MinSupportedYear {
+		fmt.Fprintf(os.Stderr, "错误: 出生年份需要在 %d 年及以后\n", calendar.MinSupportedYear)
+		return 2
+	}
+	solarAge, nominalAge := calendar.Age(birth, now)
+	fmt.Printf("%s 出生：周岁 %d，虚岁 %d，%s\n", value, solarAge, nominalAge, calendar.WesternZodiac(birth))
+	return 0
+}
+
+// runLunarMonths prints one "date month" line for every 初一 in year and
+// returns the process exit code.
+func runLunarMonths(year int) int {
+	starts, err := calendar.NewService().LunarMonthStarts(year)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 年份需要在 %d-%d 之间 (收到 %d)\n", calendar.MinSupportedYear, calendar.MaxSupportedYear, year)
+		return 2
+	}
+	for _, start := range starts {
+		fmt.Printf("%s %s\n", start.Date.Format("2006-01-02"), start.MonthName)
+	}
+	return 0
+}
+
+// runLunarNewYear prints the date of 正月初一 in year and returns the process
+// exit code.
+func runLunarNewYear(year int) int {
+	date, err := calendar.LunarNewYear(year)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 年份需要在 %d-%d 之间 (收到 %d)\n", calendar.MinSupportedYear, calendar.MaxSupportedYear, year)
+		return 2
+	}
+	fmt.Printf("%s 周%s\n", date.Format("2006-01-02"), chineseWeekdayNames[date.Weekday()])
+	return 0
+}
+
+// runIsWorkday prints whether the given date is a working day, with weekend
+// as the rest days of the week, and returns the process exit code: 0 for a
+// working day, 1 for a rest day and 2 when the date can't be parsed.
+func runIsWorkday(value string, data map[string]map[string]*holidays.HolidayEntry, weekend []time.Weekday) int {
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法将 %q 解析为日期，格式应为 YYYY-MM-DD\n", value)
+		return 2
+	}
+	if data == nil {
+		fmt.Fprintln(os.Stderr, "警告: 未加载节假日数据，仅按周末判断")
+	}
+	svc := calendar.NewService(calendar.WithHolidays(data), calendar.WithWeekend(weekend...))
+	working, reason := svc.IsWorkingDay(date)
+	if working {
+		fmt.Printf("%s 是工作日（%s）\n", value, reason)
+		return 0
+	}
+	fmt.Printf("%s 不是工作日（%s）\n", value, reason)
+	return 1
 }
 
+// minBareYear is the smallest value a lone argument may have to be read as a
+// year. Anything below it (other than 1..12) is ambiguous and must be spelled
+// out with -y.
+const minBareYear = 100
+
 func parseRequest(showYear bool, args []string) (calendar.Request, error) {
-	now := time.Now()
+	now := clock().In(location)
 	year := now.Year()
 	month := int(now.Month())
 
@@ -133,6 +689,16 @@ func parseRequest(showYear bool, args []string) (calendar.Request, error) {
 	case 0:
 		// defaults
 	case 1:
+		if offset, ok := parseOffset(args[0]); ok {
+			base := calendar.Request{Year: year, Month: month}
+			if showYear {
+				base = base.Add(offset, 0)
+			} else {
+				base = base.Add(0, offset)
+			}
+			year, month = base.Year, base.Month
+			break
+		}
 		if showYear {
 			val, err := parseNumber(args[0], "year")
 			if err != nil {
@@ -142,11 +708,18 @@ func parseRequest(showYear bool, args []string) (calendar.Request, error) {
 		} else {
 			val, err := parseNumber(args[0], "month/year")
 			if err != nil {
-				return calendar.Request{}, err
+				m, ok := parseMonthName(args[0])
+				if !ok {
+					return calendar.Request{}, err
+				}
+				val = m
 			}
-			if val >= 1 && val <= 12 {
+			switch {
+			case val >= 1 && val <= 12:
 				month = val
-			} else {
+			case val < minBareYear:
+				return calendar.Request{}, fmt.Errorf("无法确定 %d 是月份还是年份；请用 -y %d 表示年份", val, val)
+			default:
 				year = val
 				showYear = true
 			}
@@ -161,7 +734,11 @@ func parseRequest(showYear bool, args []string) (calendar.Request, error) {
 		}
 		m, err := parseNumber(args[1], "month")
 		if err != nil {
-			return calendar.Request{}, err
+			name, ok := parseMonthName(args[1])
+			if !ok {
+				return calendar.Request{}, err
+			}
+			m = name
 		}
 		if m < 1 || m > 12 {
 			return calendar.Request{}, fmt.Errorf("月份需要在 1-12 之间 (收到 %d)", m)
@@ -183,6 +760,44 @@ func parseRequest(showYear bool, args []string) (calendar.Request, error) {
 	return req.Normalize(), nil
 }
 
+// maxYearRange caps how many years --from/--to may span.
+const maxYearRange = 100
+
+// parseYearRange validates --from/--to and returns the year-mode request for
+// the first year; PlainOptions.ToYear carries the rest of the range.
+func parseYearRange(from, to int) (calendar.Request, error) {
+	if from == 0 || to == 0 {
+		return calendar.Request{}, errors.New("--from 和 --to 需要同时指定")
+	}
+	for _, year := range []int{from, to} {
+		if year < calendar.MinSupportedYear || year > calendar.MaxSupportedYear {
+			return calendar.Request{}, fmt.Errorf("年份需要在 %d-%d 之间 (收到 %d)", calendar.MinSupportedYear, calendar.MaxSupportedYear, year)
+		}
+	}
+	if from > to {
+		return calendar.Request{}, fmt.Errorf("--from %d 晚于 --to %d", from, to)
+	}
+	if span := to - from + 1; span > maxYearRange {
+		return calendar.Request{}, fmt.Errorf("年份范围最多 %d 年 (收到 %d 年)", maxYearRange, span)
+	}
+	return calendar.Request{Year: from, Month: 1, Mode: calendar.ModeYear}, nil
+}
+
+// parseOffset recognizes a signed relative offset such as "+1" or "-2". An
+// unsigned number is an absolute month or year, not an offset.
+func parseOffset(value string) (int, bool) {
+	if len(value) < 2 || (value[0] != '+' && value[0] != '-') {
+		return 0, false
+	}
+	for _, r := range value[1:] {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
 func parseNumber(value string, field string) (int, error) {
 	n, err := strconv.Atoi(value)
 	if err != nil {
@@ -191,3 +806,139 @@ func parseNumber(value string, field string) (int, error) {
 	return n, nil
 }
 
+var chineseWeekdayNames = []string{"日", "一", "二", "三", "四", "五", "六"}
+
+// parseWeekday accepts English weekday names or abbreviations ("mon",
+// "Monday"), Chinese ones ("周一", "星期日"), or a number: 0-6 follows
+// time.Weekday (0=Sunday) and ISO 7 also means Sunday, so 1-6 agree in both
+// conventions.
+func parseWeekday(value string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if n, err := strconv.Atoi(name); err == nil {
+		if n < 0 || n > 7 {
+			return 0, fmt.Errorf("星期需要在 0-7 之间 (收到 %d)", n)
+		}
+		return time.Weekday(n % 7), nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		zh := chineseWeekdayNames[d]
+		if name == full || name == full[:3] || name == "周"+zh || name == "星期"+zh {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("无法识别的星期 %q，可用 sun、mon、monday 等名称或数字 0-7", value)
+}
+
+// parseWeekend parses a comma-separated list of parseWeekday values, such as
+// "fri,sat", into the days of the --weekend flag.
+func parseWeekend(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, field := range strings.Split(value, ",") {
+		day, err := parseWeekday(field)
+		if err != nil {
+			return nil, fmt.Errorf("--weekend: %w", err)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseMarks parses the comma-separated YYYY-MM-DD dates of --mark. An
+// empty value marks nothing.
+func parseMarks(value string) ([]time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var dates []time.Time
+	for _, field := range strings.Split(value, ",") {
+		date, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(field), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("--mark: 无法解析日期 %q，需要 YYYY-MM-DD 格式", strings.TrimSpace(field))
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// marksOutside returns the marks that fall outside the months req shows,
+// with toYear and fiscalStart extending a year request as in
+// render.PlainOptions.
+func marksOutside(marks []time.Time, req calendar.Request, toYear, fiscalStart int) []time.Time {
+	req = req.Normalize()
+	month, months := req.Month, 1
+	if req.Mode == calendar.ModeYear {
+		month, months = max(fiscalStart, 1), 12*max(toYear-req.Year+1, 1)
+	}
+	start := time.Date(req.Year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, months, 0)
+	var outside []time.Time
+	for _, mark := range marks {
+		if mark.Before(start) || !mark.Before(end) {
+			outside = append(outside, mark)
+		}
+	}
+	return outside
+}
+
+// parseTimeZone loads the IANA time zone name of --tz; "" is time.Local.
+func parseTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("--tz: 无法识别的时区 %q，需要 IANA 名称，如 Asia/Shanghai", name)
+	}
+	return loc, nil
+}
+
+// parseAmbiguousWidth picks the column count of ambiguous-width runes: the
+// --ambiguous-width flag when set, then $LUCAL_AMBIGUOUS_WIDTH, then 1.
+func parseAmbiguousWidth(flagValue int, env string) (int, error) {
+	if flagValue != 0 {
+		if flagValue != 1 && flagValue != 2 {
+			return 0, fmt.Errorf("--ambiguous-width 只能为 1 或 2 (收到 %d)", flagValue)
+		}
+		return flagValue, nil
+	}
+	env = strings.TrimSpace(env)
+	if env == "" {
+		return 1, nil
+	}
+	if env != "1" && env != "2" {
+		return 0, fmt.Errorf("LUCAL_AMBIGUOUS_WIDTH 只能为 1 或 2 (收到 %q)", env)
+	}
+	return strconv.Atoi(env)
+}
+
+var chineseMonthNames = []string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"}
+
+// parseMonthName maps English month names or abbreviations ("Nov",
+// "November", "Sept") and Chinese month names ("十一月", "11月") to 1..12.
+func parseMonthName(value string) (int, bool) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if name == "" {
+		return 0, false
+	}
+	for m := time.January; m <= time.December; m++ {
+		full := strings.ToLower(m.String())
+		if name == full || name == full[:3] {
+			return int(m), true
+		}
+	}
+	if name == "sept" {
+		return int(time.September), true
+	}
+	for i, cn := range chineseMonthNames {
+		if name == cn {
+			return i + 1, true
+		}
+	}
+	if digits, ok := strings.CutSuffix(name, "月"); ok {
+		if n, err := strconv.Atoi(digits); err == nil && n >= 1 && n <= 12 {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+func TestParseRequestSingleArg(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantMode  calendar.ViewMode
+		wantYear  int
+		wantMonth int
+	}{
+		{"month", []string{"9"}, calendar.ModeMonth, 0, 9},
+		{"december", []string{"12"}, calendar.ModeMonth, 0, 12},
+		{"year", []string{"1983"}, calendar.ModeYear, 1983, 0},
+		{"three digit year", []string{"100"}, calendar.ModeYear, 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := parseRequest(false, tt.args)
+			if err != nil {
+				t.Fatalf("parseRequest(%v) returned error: %v", tt.args, err)
+			}
+			if req.Mode != tt.wantMode {
+				t.Fatalf("mode=%v want %v", req.Mode, tt.wantMode)
+			}
+			if tt.wantYear != 0 && req.Year != tt.wantYear {
+				t.Fatalf("year=%d want %d", req.Year, tt.wantYear)
+			}
+			if tt.wantMonth != 0 && req.Month != tt.wantMonth {
+				t.Fatalf("month=%d want %d", req.Month, tt.wantMonth)
+			}
+		})
+	}
+}
+
+func TestParseRequestAmbiguousArg(t *testing.T) {
+	for _, arg := range []string{"0", "13", "99"} {
+		t.Run(arg, func(t *testing.T) {
+			if _, err := parseRequest(false, []string{arg}); err == nil {
+				t.Fatalf("expected error for ambiguous argument %q", arg)
+			}
+		})
+	}
+}
+
+func TestParseRequestYearFlagAllowsSmallYears(t *testing.T) {
+	req, err := parseRequest(true, []string{"13"})
+	if err != nil {
+		t.Fatalf("parseRequest returned error: %v", err)
+	}
+	if req.Mode != calendar.ModeYear || req.Year != 13 {
+		t.Fatalf("expected year 13 in year mode, got %+v", req)
+	}
+}
+
+func TestParseRequestMonthNames(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want int
+	}{
+		{"Nov", 11},
+		{"November", 11},
+		{"十一月", 11},
+		{"sept", 9},
+		{"3月", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			req, err := parseRequest(false, []string{tt.arg})
+			if err != nil {
+				t.Fatalf("parseRequest(%q) returned error: %v", tt.arg, err)
+			}
+			if req.Mode != calendar.ModeMonth || req.Month != tt.want {
+				t.Fatalf("parseRequest(%q)=%+v want month %d", tt.arg, req, tt.want)
+			}
+		})
+	}
+
+	req, err := parseRequest(false, []string{"2012", "Dec"})
+	if err != nil {
+		t.Fatalf("parseRequest(2012 Dec) returned error: %v", err)
+	}
+	if req.Year != 2012 || req.Month != 12 {
+		t.Fatalf("expected December 2012, got %+v", req)
+	}
+}
+
+func TestParseRequestInvalidMonthName(t *testing.T) {
+	if _, err := parseRequest(false, []string{"Smarch"}); err == nil {
+		t.Fatalf("expected error for unknown month name")
+	}
+}
+
+func TestParseYearRange(t *testing.T) {
+	req, err := parseYearRange(2023, 2025)
+	if err != nil {
+		t.Fatalf("parseYearRange returned error: %v", err)
+	}
+	if req.Year != 2023 || req.Mode != calendar.ModeYear {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if _, err := parseYearRange(2025, 2025); err != nil {
+		t.Fatalf("a single-year range should be accepted, got %v", err)
+	}
+	for _, tt := range []struct{ from, to int }{
+		{2025, 2023},
+		{2025, 0},
+		{0, 2025},
+		{1899, 1901},
+		{2999, 3001},
+		{1900, 1900 + maxYearRange},
+	} {
+		if _, err := parseYearRange(tt.from, tt.to); err == nil {
+			t.Fatalf("parseYearRange(%d, %d) should fail", tt.from, tt.to)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{calendar.ErrYearOutOfRange, "year_out_of_range"},
+		{fmt.Errorf("render: %w", calendar.ErrInvalidMonth), "invalid_month"},
+		{&calendar.MonthError{Month: 13}, "invalid_month"},
+		{&holidays.HolidayParseError{Path: "h.json", Err: errors.New("bad")}, "invalid_holiday_file"},
+		{argumentError{errors.New("参数过多")}, "invalid_argument"},
+		{strictError{"h.json", []holidays.Warning{{Message: "节假日文件为空"}}}, "holiday_warning"},
+		{errors.New("boom"), "error"},
+	}
+	for _, tt := range tests {
+		if got := errorCode(tt.err); got != tt.want {
+			t.Fatalf("errorCode(%v)=%q want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestStrictError(t *testing.T) {
+	err := strictError{"h.json", []holidays.Warning{
+		{Year: "2025", Key: "10-01", Message: "日期字段 2025-10-02 与键值不一致，以日期字段为准"},
+		{Message: "节假日文件为空"},
+	}}
+	want := "节假日文件 h.json 有 2 个问题 (--strict): 2025/10-01: 日期字段 2025-10-02 与键值不一致，以日期字段为准; 节假日文件为空"
+	if got := err.Error(); got != want {
+		t.Fatalf("Error()=%q want %q", got, want)
+	}
+}
+
+func TestHolidayNotification(t *testing.T) {
+	day := calendar.Day{
+		Date:        time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local),
+		HolidayInfo: &holidays.HolidayInfo{IsHoliday: true, Name: "国庆节"},
+	}
+	message := holidayGreeting(day)
+	if message != "今天是 国庆节，假期愉快！" {
+		t.Fatalf("holidayGreeting=%q", message)
+	}
+	got := notifyArgs("notify-send --app-name=lucal {name} {message} {date}", day, message)
+	want := []string{"notify-send", "--app-name=lucal", "国庆节", message, "2025-10-01"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("notifyArgs=%q want %q", got, want)
+	}
+
+	for _, info := range []*holidays.HolidayInfo{nil, {IsHoliday: false, Name: "国庆节"}} {
+		day.HolidayInfo = info
+		if message := holidayGreeting(day); message != "" {
+			t.Fatalf("expected no greeting for %+v, got %q", info, message)
+		}
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Weekday
+	}{
+		{"sun", time.Sunday},
+		{"Mon", time.Monday},
+		{"saturday", time.Saturday},
+		{"周一", time.Monday},
+		{"星期日", time.Sunday},
+		{"0", time.Sunday},
+		{"1", time.Monday},
+		{"7", time.Sunday},
+	}
+	for _, tt := range tests {
+		got, err := parseWeekday(tt.in)
+		if err != nil || got != tt.want {
+			t.Fatalf("parseWeekday(%q)=%v, %v want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"8", "-1", "funday", ""} {
+		if _, err := parseWeekday(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseWeekend(t *testing.T) {
+	days, err := parseWeekend("fri, 星期六")
+	if err != nil || len(days) != 2 || days[0] != time.Friday || days[1] != time.Saturday {
+		t.Fatalf("parseWeekend=%v, %v want [Friday Saturday]", days, err)
+	}
+	if _, err := parseWeekend("sat,someday"); err == nil {
+		t.Fatal("expected an error for an unknown day")
+	}
+}
+
+func TestParseMarks(t *testing.T) {
+	marks, err := parseMarks("2025-11-11, 2025-11-25")
+	if err != nil || len(marks) != 2 || marks[1].Day() != 25 {
+		t.Fatalf("parseMarks=%v, %v want 2025-11-11 and 2025-11-25", marks, err)
+	}
+	if _, err := parseMarks("2025-11-11,11/25"); err == nil {
+		t.Fatal("expected an error for a malformed date")
+	}
+	if marks, err := parseMarks(""); err != nil || marks != nil {
+		t.Fatalf("parseMarks(\"\")=%v, %v want nothing", marks, err)
+	}
+}
+
+func TestParseTimeZone(t *testing.T) {
+	if loc, err := parseTimeZone(""); err != nil || loc != time.Local {
+		t.Fatalf("parseTimeZone(\"\")=%v, %v want time.Local", loc, err)
+	}
+	if loc, err := parseTimeZone("UTC"); err != nil || loc.String() != "UTC" {
+		t.Fatalf("parseTimeZone(\"UTC\")=%v, %v want UTC", loc, err)
+	}
+	if _, err := parseTimeZone("Mars/Olympus_Mons"); err == nil {
+		t.Fatal("expected an error for an unknown zone")
+	}
+}
+
+func TestMarksOutside(t *testing.T) {
+	marks, _ := parseMarks("2025-10-31,2025-11-01,2025-11-30,2025-12-01,2026-03-01")
+	tests := []struct {
+		req         calendar.Request
+		toYear      int
+		fiscalStart int
+		want        int
+	}{
+		{calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeMonth}, 0, 0, 3},
+		{calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeYear}, 0, 0, 1},
+		{calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeYear}, 2026, 0, 0},
+		{calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeYear}, 0, 11, 1},
+	}
+	for _, tt := range tests {
+		if got := marksOutside(marks, tt.req, tt.toYear, tt.fiscalStart); len(got) != tt.want {
+			t.Errorf("marksOutside(%+v, %d, %d)=%v want %d dates", tt.req, tt.toYear, tt.fiscalStart, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmbiguousWidth(t *testing.T) {
+	tests := []struct {
+		flag    int
+		env     string
+		want    int
+		wantErr bool
+	}{
+		{0, "", 1, false},
+		{0, "2", 2, false},
+		{1, "2", 1, false},
+		{2, "", 2, false},
+		{3, "", 0, true},
+		{0, "wide", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAmbiguousWidth(tt.flag, tt.env)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Fatalf("parseAmbiguousWidth(%d, %q) = %d, %v", tt.flag, tt.env, got, err)
+		}
+	}
+}
+
+func TestCreateOutputMakesParentDirs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exports", "2025", "cal.json")
+	file, err := createOutput(path)
+	if err != nil {
+		t.Fatalf("createOutput failed: %v", err)
+	}
+	if _, err := file.WriteString("{}"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "{}" {
+		t.Fatalf("expected {} in %s, got %q, %v", path, data, err)
+	}
+}
+
+func TestParseRequestRelativeOffset(t *testing.T) {
+	clock = func() time.Time { return time.Date(2025, 11, 15, 12, 0, 0, 0, time.Local) }
+	defer func() { clock = time.Now }()
+	tests := []struct {
+		showYear bool
+		arg      string
+		want     calendar.Request
+	}{
+		{false, "+13", calendar.Request{Year: 2026, Month: 12}},
+		{false, "+2", calendar.Request{Year: 2026, Month: 1}},
+		{false, "-1", calendar.Request{Year: 2025, Month: 10}},
+		{false, "-11", calendar.Request{Year: 2024, Month: 12}},
+		{false, "+0", calendar.Request{Year: 2025, Month: 11}},
+		{true, "+1", calendar.Request{Year: 2026, Month: 11}},
+	}
+	for _, tt := range tests {
+		req, err := parseRequest(tt.showYear, []string{tt.arg})
+		if err != nil {
+			t.Fatalf("parseRequest(%q) returned error: %v", tt.arg, err)
+		}
+		if req.Year != tt.want.Year || req.Month != tt.want.Month {
+			t.Fatalf("parseRequest(%q)=%d-%02d want %d-%02d", tt.arg, req.Year, req.Month, tt.want.Year, tt.want.Month)
+		}
+	}
+	if _, ok := parseOffset("+"); ok {
+		t.Fatal("a bare sign is not an offset")
+	}
+	if _, ok := parseOffset("9"); ok {
+		t.Fatal("an unsigned number is not an offset")
+	}
+}
+
+func TestInspectCache(t *testing.T) {
+	now := time.Now()
+	path := filepath.Join(t.TempDir(), "holidays.json")
+	if status := inspectCache(path, now); status.Exists || status.Valid || status.Error != "" {
+		t.Fatalf("expected a missing cache, got %+v", status)
+	}
+
+	content := `[{"year": "2025", "holiday": {"10-01": {"holiday": true, "name": "国庆节", "date": "2025-10-01"}}}]`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	status := inspectCache(path, now)
+	if !status.Exists || !status.Valid || status.Years == nil || status.Years.Min != 2025 || status.Years.Max != 2025 {
+		t.Fatalf("expected a fresh cache covering 2025, got %+v", status)
+	}
+
+	stale := now.AddDate(0, -7, 0)
+	if err := os.Chtimes(path, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	if status := inspectCache(path, now); status.Valid || status.AgeDays < 200 {
+		t.Fatalf("expected a stale cache older than 200 days, got %+v", status)
+	}
+}
+
+func TestWithEnvArgs(t *testing.T) {
+	args := withEnvArgs("  -N\t--first-day=mon ", []string{"--first-day=sun", "2025"})
+	want := []string{"-N", "--first-day=mon", "--first-day=sun", "2025"}
+	if fmt.Sprint(args) != fmt.Sprint(want) {
+		t.Fatalf("withEnvArgs=%q want %q", args, want)
+	}
+
+	fs := flag.NewFlagSet("lucal", flag.ContinueOnError)
+	first := fs.String("first-day", "sun", "")
+	noColor := fs.Bool("N", false, "")
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if *first != "sun" || !*noColor || fs.Arg(0) != "2025" {
+		t.Fatalf("expected the command line to override LUCAL_ARGS, got first-day=%s N=%v args=%v", *first, *noColor, fs.Args())
+	}
+	if got := withEnvArgs("", []string{"9"}); len(got) != 1 || got[0] != "9" {
+		t.Fatalf("expected args untouched without LUCAL_ARGS, got %q", got)
+	}
+}
+
+func TestRunWatchRedrawsUntilInterrupted(t *testing.T) {
+	signals := make(chan os.Signal, 2)
+	want := 1
+	if len(resizeSignals) > 0 {
+		signals <- resizeSignals[0]
+		want++
+	}
+	signals <- os.Interrupt
+	draws := 0
+	var out strings.Builder
+	code := runWatch(&out, func(w io.Writer) error {
+		draws++
+		_, err := fmt.Fprintln(w, "calendar")
+		return err
+	}, time.Hour, signals)
+	if code != 0 {
+		t.Fatalf("runWatch exit code=%d want 0", code)
+	}
+	if draws != want {
+		t.Fatalf("draws=%d want %d", draws, want)
+	}
+	if !strings.HasPrefix(out.String(), clearScreen+"calendar") {
+		t.Fatalf("expected every draw to clear the screen first, got %q", out.String())
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/lululau/lucal/internal/calendar"
+)
+
+// holidayGreeting is the --notify message for today, or "" unless it is a
+// day off of a statutory holiday.
+func holidayGreeting(today calendar.Day) string {
+	if today.HolidayInfo == nil || !today.HolidayInfo.IsHoliday {
+		return ""
+	}
+	return fmt.Sprintf("今天是 %s，假期愉快！", today.HolidayInfo.Name)
+}
+
+// notifyArgs splits the --notify-command template on whitespace and fills
+// {message}, {name} and {date} in each word afterwards, so values containing
+// spaces stay a single argument. No shell is involved.
+func notifyArgs(template string, today calendar.Day, message string) []string {
+	replacer := strings.NewReplacer(
+		"{message}", message,
+		"{name}", today.HolidayInfo.Name,
+		"{date}", today.Date.Format("2006-01-02"),
+	)
+	args := strings.Fields(template)
+	for i, arg := range args {
+		args[i] = replacer.Replace(arg)
+	}
+	return args
+}
+
+// notifyHoliday greets on stderr, or by running command when it is set, if
+// today is a holiday. A failing command is reported as a warning.
+func notifyHoliday(today calendar.Day, command string) {
+	message := holidayGreeting(today)
+	if message == "" {
+		return
+	}
+	if command == "" {
+		fmt.Fprintln(os.Stderr, message)
+		return
+	}
+	args := notifyArgs(command, today, message)
+	if len(args) == 0 {
+		return
+	}
+	if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 通知命令 %s 执行失败: %v %s\n", args[0], err, strings.TrimSpace(string(out)))
+	}
+}
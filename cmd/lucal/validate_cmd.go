@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lululau/lucal/internal/holidays"
+	"github.com/lululau/lucal/internal/render"
+)
+
+// validation is the --format=json report of `lucal validate-holidays`.
+type validation struct {
+	Source   string          `json:"source"`
+	Valid    bool            `json:"valid"`
+	Error    string          `json:"error,omitempty"`
+	Warnings []string        `json:"warnings"`
+	Years    int             `json:"years"`
+	Range    *cacheYearRange `json:"range,omitempty"`
+	Holidays int             `json:"holidays"`
+	Workdays int             `json:"workdays"`
+}
+
+// runValidateHolidays implements `lucal validate-holidays [--strict]
+// [--format text|json] path`, linting a holiday file or URL without rendering.
+// It returns the process exit code: 0 when the file is usable, 1 when it
+// cannot be loaded or, with --strict, has warnings, and 2 for bad arguments.
+// strict and format default to the global flags.
+func runValidateHolidays(args []string, strict bool, format string) int {
+	fs := flag.NewFlagSet("validate-holidays", flag.ContinueOnError)
+	strictFlag := fs.Bool("strict", strict, "有任何警告时退出码为 1")
+	formatFlag := fs.String("format", format, "报告格式: text 或 json")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: lucal validate-holidays [--strict] [--format text|json] <文件或 URL>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *formatFlag != render.FormatText && *formatFlag != render.FormatJSON {
+		fmt.Fprintf(os.Stderr, "错误: 不支持的报告格式 %q，可选 text 或 json\n", *formatFlag)
+		return 2
+	}
+
+	result := validation{Source: fs.Arg(0), Warnings: []string{}}
+	report, err := holidays.Validate(result.Source)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		for _, w := range report.Warnings {
+			result.Warnings = append(result.Warnings, w.String())
+		}
+		result.Years = report.Years
+		if report.Coverage != nil {
+			result.Range = &cacheYearRange{Min: report.Coverage.MinYear, Max: report.Coverage.MaxYear}
+		}
+		result.Holidays = report.Holidays
+		result.Workdays = report.Workdays
+		result.Valid = !*strictFlag || len(report.Warnings) == 0
+	}
+
+	if *formatFlag == render.FormatJSON {
+		payload, _ := json.Marshal(result)
+		fmt.Println(string(payload))
+	} else {
+		printValidation(result)
+	}
+	if !result.Valid {
+		return 1
+	}
+	return 0
+}
+
+func printValidation(result validation) {
+	fmt.Printf("节假日文件：%s\n", result.Source)
+	if result.Error != "" {
+		fmt.Printf("错误：%s\n", result.Error)
+		return
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("警告：%s\n", w)
+	}
+	years := fmt.Sprintf("%d 年", result.Years)
+	if result.Range != nil {
+		years += fmt.Sprintf("（%d-%d）", result.Range.Min, result.Range.Max)
+	}
+	fmt.Printf("共 %s，节假日 %d 天，调休上班 %d 天，警告 %d 条\n", years, result.Holidays, result.Workdays, len(result.Warnings))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// runWatch redraws with draw every interval, and right away whenever a
+// signal in resizeSignals arrives, until any other signal does. draw runs
+// with a fresh clock and terminal width each time, so the today marker and
+// layout follow midnight and pane resizes. It returns the process exit code.
+func runWatch(w io.Writer, draw func(io.Writer) error, interval time.Duration, signals <-chan os.Signal) int {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fmt.Fprint(w, clearScreen)
+		if err := draw(w); err != nil {
+			fmt.Fprintln(os.Stderr, "错误:", err)
+			return 1
+		}
+		select {
+		case <-ticker.C:
+		case sig := <-signals:
+			if !isResizeSignal(sig) {
+				return 0
+			}
+		}
+	}
+}
+
+func isResizeSignal(sig os.Signal) bool {
+	for _, s := range resizeSignals {
+		if sig == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// resizeSignals is empty where terminals don't signal resizes; --watch then
+// picks up the new width on its next interval.
+var resizeSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// resizeSignals trigger an immediate --watch redraw.
+var resizeSignals = []os.Signal{syscall.SIGWINCH}
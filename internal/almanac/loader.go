@@ -0,0 +1,56 @@
+package almanac
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Entry lists the activities a day is suitable (宜) and unsuitable (忌) for.
+type Entry struct {
+	Yi []string `json:"yi"`
+	Ji []string `json:"ji"`
+}
+
+// LoadFromFile loads almanac data from a JSON file keyed by the lunar date
+// including its sexagenary year, as produced by Day.LunarDateStringWithYear:
+//
+//	{"乙巳年九月廿二": {"yi": ["祭祀", "出行"], "ji": ["动土"]}}
+//
+// Blank activities are dropped, and so are entries left with nothing.
+func LoadFromFile(path string) (map[string]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read almanac file: %w", err)
+	}
+
+	var raw map[string]Entry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse almanac JSON: %w", err)
+	}
+
+	result := make(map[string]Entry, len(raw))
+	for key, entry := range raw {
+		key = strings.TrimSpace(key)
+		if !strings.Contains(key, "年") {
+			return nil, fmt.Errorf("invalid almanac key %q, expected a lunar date such as 乙巳年九月廿二", key)
+		}
+		entry = Entry{Yi: trimAll(entry.Yi), Ji: trimAll(entry.Ji)}
+		if len(entry.Yi) == 0 && len(entry.Ji) == 0 {
+			continue
+		}
+		result[key] = entry
+	}
+	return result, nil
+}
+
+func trimAll(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
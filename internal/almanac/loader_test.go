@@ -0,0 +1,36 @@
+package almanac
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "almanac.json")
+	content := `{"乙巳年九月廿二": {"yi": [" 祭祀 ", ""], "ji": ["动土"]}, "乙巳年九月廿三": {"yi": [" "]}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	data, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	entry := data["乙巳年九月廿二"]
+	if len(entry.Yi) != 1 || entry.Yi[0] != "祭祀" || len(entry.Ji) != 1 {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if _, ok := data["乙巳年九月廿三"]; ok {
+		t.Fatalf("expected empty entry to be dropped")
+	}
+}
+
+func TestLoadFromFileInvalidKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "almanac.json")
+	if err := os.WriteFile(path, []byte(`{"2025-11-11": {"yi": ["祭祀"]}}`), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	if _, err := LoadFromFile(path); err == nil {
+		t.Fatalf("expected error for a gregorian key")
+	}
+}
@@ -0,0 +1,62 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	calendarlib "github.com/Lofanmi/chinese-calendar-golang/calendar"
+)
+
+// Age returns a person's age on asOf counted two ways. solarAge is the usual
+// Gregorian age (周岁), going up on each birthday. nominalAge follows the 虚岁
+// convention: 1 at birth, going up at every Lunar New Year. nominalAge is 0
+// when either date is outside MinSupportedYear..MaxSupportedYear.
+func Age(birth, asOf time.Time) (solarAge, nominalAge int) {
+	solarAge = asOf.Year() - birth.Year()
+	if asOf.Month() < birth.Month() || (asOf.Month() == birth.Month() && asOf.Day() < birth.Day()) {
+		solarAge--
+	}
+	birthLunar, ok1 := lunarYearOf(birth)
+	asOfLunar, ok2 := lunarYearOf(asOf)
+	if ok1 && ok2 {
+		nominalAge = asOfLunar - birthLunar + 1
+	}
+	return solarAge, nominalAge
+}
+
+// lunarEpoch is the first day the lunar backend has data for, 正月初一 of
+// 1900; it panics on earlier dates.
+var lunarEpoch = time.Date(1900, time.January, 31, 0, 0, 0, 0, time.Local)
+
+// LunarNewYear returns local midnight of 正月初一 of the lunar year that
+// begins in the Gregorian year, e.g. 2025-01-29. It fails with
+// ErrYearOutOfRange outside MinSupportedYear..MaxSupportedYear.
+func LunarNewYear(year int) (time.Time, error) {
+	if err := checkYear(year); err != nil {
+		return time.Time{}, err
+	}
+	// 正月初一 always falls between January 21 and February 20. Scanning
+	// Gregorian days works for the whole supported range, unlike the
+	// backend's lunar-to-solar conversion, which fails for 1900.
+	day := time.Date(year, time.January, 21, 0, 0, 0, 0, time.Local)
+	if day.Before(lunarEpoch) {
+		day = lunarEpoch
+	}
+	for ; day.Month() <= time.February; day = day.AddDate(0, 0, 1) {
+		lunar := calendarlib.BySolar(int64(year), int64(day.Month()), int64(day.Day()), 12, 0, 0).Lunar
+		if lunar.GetMonth() == 1 && lunar.GetDay() == 1 && !lunar.IsLeapMonth() {
+			return day, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%d 年未找到正月初一", year)
+}
+
+// lunarYearOf returns the lunar year t falls in, which changes at Lunar New
+// Year rather than on January 1.
+func lunarYearOf(t time.Time) (int, bool) {
+	if t.Year() < MinSupportedYear || t.Year() > MaxSupportedYear {
+		return 0, false
+	}
+	cal := calendarlib.BySolar(int64(t.Year()), int64(t.Month()), int64(t.Day()), 12, 0, 0)
+	return int(cal.Lunar.GetYear()), true
+}
@@ -0,0 +1,58 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAgeAroundLunarNewYear(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name         string
+		birth, asOf  time.Time
+		solar, lunar int
+	}{
+		// Lunar New Year fell on 2000-02-05 and 2025-01-29.
+		{"day of birth", date(2000, 2, 4), date(2000, 2, 4), 0, 1},
+		{"born on new year's eve", date(2000, 2, 4), date(2000, 2, 5), 0, 2},
+		{"before new year", date(2000, 6, 1), date(2025, 1, 28), 24, 25},
+		{"after new year", date(2000, 6, 1), date(2025, 1, 29), 24, 26},
+		{"after birthday", date(2000, 6, 1), date(2025, 6, 1), 25, 26},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			solar, lunar := Age(tt.birth, tt.asOf)
+			if solar != tt.solar || lunar != tt.lunar {
+				t.Fatalf("Age()=(%d, %d) want (%d, %d)", solar, lunar, tt.solar, tt.lunar)
+			}
+		})
+	}
+}
+
+func TestLunarNewYear(t *testing.T) {
+	tests := []struct {
+		year int
+		want string
+	}{
+		{1900, "1900-01-31"},
+		{2000, "2000-02-05"},
+		{2024, "2024-02-10"},
+		{2025, "2025-01-29"},
+		{2026, "2026-02-17"},
+	}
+	for _, tt := range tests {
+		got, err := LunarNewYear(tt.year)
+		if err != nil {
+			t.Fatalf("LunarNewYear(%d) returned error: %v", tt.year, err)
+		}
+		if got.Format("2006-01-02") != tt.want || got.Hour() != 0 {
+			t.Fatalf("LunarNewYear(%d)=%v want %s", tt.year, got, tt.want)
+		}
+	}
+	if _, err := LunarNewYear(MinSupportedYear - 1); !errors.Is(err, ErrYearOutOfRange) {
+		t.Fatalf("expected ErrYearOutOfRange, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrYearOutOfRange indicates the requested year is unsupported.
+	ErrYearOutOfRange = fmt.Errorf("year must be between %d and %d", MinSupportedYear, MaxSupportedYear)
+	// ErrInvalidMonth indicates the month is not in the 1..12 range.
+	ErrInvalidMonth = errors.New("month must be between 1 and 12")
+)
+
+// YearRangeError reports a year outside Min..Max. It matches
+// ErrYearOutOfRange with errors.Is.
+type YearRangeError struct {
+	Year, Min, Max int
+}
+
+func (e *YearRangeError) Error() string {
+	return fmt.Sprintf("year %d out of range: must be between %d and %d", e.Year, e.Min, e.Max)
+}
+
+// Is makes errors.Is(err, ErrYearOutOfRange) hold.
+func (e *YearRangeError) Is(target error) bool {
+	return target == ErrYearOutOfRange
+}
+
+// MonthError reports a month outside 1..12. It matches ErrInvalidMonth with
+// errors.Is.
+type MonthError struct {
+	Month int
+}
+
+func (e *MonthError) Error() string {
+	return fmt.Sprintf("month %d out of range: must be between 1 and 12", e.Month)
+}
+
+// Is makes errors.Is(err, ErrInvalidMonth) hold.
+func (e *MonthError) Is(target error) bool {
+	return target == ErrInvalidMonth
+}
+
+func checkYear(year int) error {
+	if year < MinSupportedYear || year > MaxSupportedYear {
+		return &YearRangeError{Year: year, Min: MinSupportedYear, Max: MaxSupportedYear}
+	}
+	return nil
+}
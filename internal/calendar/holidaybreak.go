@@ -0,0 +1,89 @@
+package calendar
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+// HolidayBreak is a run of consecutive days off around one or more holidays,
+// such as the 国庆节 and 中秋节 days of 2025-10-01~10-08.
+type HolidayBreak struct {
+	Start, End time.Time // first and last day of the break
+	Names      []string  // distinct holiday names in date order
+	Days       int       // length of the break, i.e. the 连休 count
+}
+
+// Name joins the holiday names of the break with "/", e.g. "国庆节/中秋节".
+// The per-day Spring Festival names of holidays.json (除夕, 初一…) read as
+// "春节".
+func (b HolidayBreak) Name() string {
+	var names []string
+	for _, name := range b.Names {
+		if name == "除夕" || strings.HasPrefix(name, "初") {
+			name = "春节"
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// HolidayBreak returns the break t belongs to: the consecutive IsHoliday
+// days around it, whatever their names and across month and year
+// boundaries. The Rest of the break's first day is its length when the
+// dataset provides one that covers at least those days and only days off
+// (holidays or weekend days), so it may extend End over an unlisted weekend;
+// otherwise the days are counted. ok is false when t is not a holiday.
+func (s *Service) HolidayBreak(t time.Time) (b HolidayBreak, ok bool) {
+	data := s.holidays()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	info := holidayInfo(data, day)
+	if info == nil || !info.IsHoliday {
+		return HolidayBreak{}, false
+	}
+	start := day
+	for {
+		prev := holidayInfo(data, start.AddDate(0, 0, -1))
+		if prev == nil || !prev.IsHoliday {
+			break
+		}
+		start = start.AddDate(0, 0, -1)
+	}
+
+	b.Start = start
+	for d := start; ; d = d.AddDate(0, 0, 1) {
+		info := holidayInfo(data, d)
+		if info == nil || !info.IsHoliday {
+			break
+		}
+		b.End = d
+		b.Days++
+		if !slices.Contains(b.Names, info.Name) {
+			b.Names = append(b.Names, info.Name)
+		}
+	}
+
+	if rest := holidayInfo(data, start).Rest; rest > b.Days && s.allDaysOff(start.AddDate(0, 0, b.Days), rest-b.Days) {
+		b.Days = rest
+		b.End = start.AddDate(0, 0, rest-1)
+	}
+	return b, true
+}
+
+// allDaysOff reports whether none of the n days from start is a working day.
+func (s *Service) allDaysOff(start time.Time, n int) bool {
+	for i := 0; i < n; i++ {
+		if working, _ := s.IsWorkingDay(start.AddDate(0, 0, i)); working {
+			return false
+		}
+	}
+	return true
+}
+
+func holidayInfo(data map[string]map[string]*holidays.HolidayEntry, day time.Time) *holidays.HolidayInfo {
+	return holidays.GetHolidayForDate(data, day.Year(), int(day.Month()), day.Day())
+}
@@ -0,0 +1,106 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lululau/lucal/internal/holidays"
+	"github.com/lululau/lucal/internal/holidays/holidaystest"
+)
+
+// addHolidays files a holiday entry per name under consecutive days from
+// start (YYYY-MM-DD), like the Spring Festival days of holidays.json.
+func addHolidays(data map[string]map[string]*holidays.HolidayEntry, start string, names ...string) {
+	day, _ := time.Parse("2006-01-02", start)
+	for _, name := range names {
+		year := day.Format("2006")
+		if data[year] == nil {
+			data[year] = make(map[string]*holidays.HolidayEntry)
+		}
+		data[year][day.Format("01-02")] = &holidays.HolidayEntry{Holiday: true, Name: name, Wage: 2, Date: day.Format("2006-01-02")}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+func TestHolidayBreak(t *testing.T) {
+	data := holidaystest.Data()
+	// 2025's National Day break includes 中秋节 on 10-06 and runs to 10-08.
+	addHolidays(data, "2025-10-06", "中秋节", "国庆节", "国庆节")
+	// The Spring Festival break spans January and February, one name a day.
+	addHolidays(data, "2025-01-28", "除夕", "初一", "初二", "初三", "初四", "初五", "初六", "初七")
+	svc := NewService(WithHolidays(data))
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name       string
+		at         time.Time
+		start, end time.Time
+		days       int
+		title      string
+	}{
+		{"national day", date(10, 3), date(10, 1), date(10, 8), 8, "国庆节/中秋节"},
+		{"mid-autumn inside the break", date(10, 6), date(10, 1), date(10, 8), 8, "国庆节/中秋节"},
+		{"spring festival from february", date(2, 2), date(1, 28), date(2, 4), 8, "春节"},
+		{"single day", date(1, 1), date(1, 1), date(1, 1), 1, "元旦"},
+	}
+	for _, tt := range tests {
+		b, ok := svc.HolidayBreak(tt.at)
+		if !ok {
+			t.Fatalf("%s: expected a break on %s", tt.name, tt.at.Format("01-02"))
+		}
+		if !b.Start.Equal(tt.start) || !b.End.Equal(tt.end) || b.Days != tt.days || b.Name() != tt.title {
+			t.Fatalf("%s: got %s %s~%s %d days, want %s %s~%s %d days", tt.name,
+				b.Name(), b.Start.Format("01-02"), b.End.Format("01-02"), b.Days,
+				tt.title, tt.start.Format("01-02"), tt.end.Format("01-02"), tt.days)
+		}
+	}
+
+	for _, day := range []time.Time{date(10, 11), date(10, 9)} {
+		if b, ok := svc.HolidayBreak(day); ok {
+			t.Fatalf("expected no break on %s, got %+v", day.Format("01-02"), b)
+		}
+	}
+}
+
+func TestHolidayBreakRest(t *testing.T) {
+	rest := func(n int) *int { return &n }
+	tests := []struct {
+		name string
+		rest *int
+		end  int // day of April
+		days int
+	}{
+		{"no rest: counted", nil, 4, 1},
+		{"rest covers the weekend", rest(3), 6, 3},
+		{"rest runs into a workday", rest(15), 4, 1},
+		{"zero rest: counted", rest(0), 4, 1},
+	}
+	for _, tt := range tests {
+		data := holidaystest.Data()
+		// 清明节 2025-04-04 is a Friday; only the Friday is listed.
+		data["2025"]["04-04"] = &holidays.HolidayEntry{Holiday: true, Name: "清明节", Wage: 3, Date: "2025-04-04", Rest: tt.rest}
+		b, ok := NewService(WithHolidays(data)).HolidayBreak(time.Date(2025, 4, 4, 0, 0, 0, 0, time.Local))
+		if !ok {
+			t.Fatalf("%s: expected a break", tt.name)
+		}
+		if b.End.Day() != tt.end || b.Days != tt.days {
+			t.Fatalf("%s: got end 04-%02d and %d days, want 04-%02d and %d", tt.name, b.End.Day(), b.Days, tt.end, tt.days)
+		}
+	}
+}
+
+func TestDayCarriesHolidayBreak(t *testing.T) {
+	view, err := NewService(WithHolidays(holidaystest.Data())).Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	for _, day := range view.Days() {
+		holiday := day.HolidayInfo != nil && day.HolidayInfo.IsHoliday
+		if holiday != (day.HolidayBreak != nil) {
+			t.Fatalf("%s: holiday=%v but break=%v", day.Date.Format("01-02"), holiday, day.HolidayBreak)
+		}
+		if holiday && day.HolidayBreak.Days != 7 {
+			t.Fatalf("%s: expected the 7-day break, got %d", day.Date.Format("01-02"), day.HolidayBreak.Days)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package calendar
+
+import "time"
+
+// nayinNames lists the 纳音 of the sexagenary cycle; each name covers two
+// consecutive pillars, starting with 甲子 and 乙丑 (海中金).
+var nayinNames = []string{
+	"海中金", "炉中火", "大林木", "路旁土", "剑锋金",
+	"山头火", "涧下水", "城头土", "白蜡金", "杨柳木",
+	"泉中水", "屋上土", "霹雳火", "松柏木", "长流水",
+	"沙中金", "山下火", "平地木", "壁上土", "金箔金",
+	"覆灯火", "天河水", "大驿土", "钗钏金", "桑柘木",
+	"大溪水", "沙中土", "天上火", "石榴木", "大海水",
+}
+
+// jiaziDay is a 甲子 day, the start of the day-pillar cycle.
+var jiaziDay = time.Date(1949, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+// nayin returns the 纳音 of the pillar at position index (0 = 甲子) of the
+// sexagenary cycle.
+func nayin(index int) string {
+	return nayinNames[mod(index, 60)/2]
+}
+
+// YearNayin returns the 纳音 of the lunar year's 干支 pillar, e.g. 覆灯火
+// for 乙巳 (2025), or "" without lunar data.
+func (d Day) YearNayin() string {
+	if !d.hasLunarData {
+		return ""
+	}
+	return nayin(d.LunarYear - 4) // 4 AD is a 甲子 year, as in ganzhiYear.
+}
+
+// DayNayin returns the 纳音 of the day pillar, e.g. 路旁土 for 2025-01-01
+// (庚午).
+func (d Day) DayNayin() string {
+	date := time.Date(d.Date.Year(), d.Date.Month(), d.Date.Day(), 0, 0, 0, 0, time.UTC)
+	return nayin(int(date.Sub(jiaziDay).Hours() / 24))
+}
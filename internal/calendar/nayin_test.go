@@ -0,0 +1,35 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNayin(t *testing.T) {
+	svc := NewService()
+	tests := []struct {
+		date                time.Time
+		yearNayin, dayNayin string
+	}{
+		{time.Date(1949, 10, 1, 0, 0, 0, 0, time.Local), "霹雳火", "海中金"},  // 己丑年 甲子日
+		{time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local), "城头土", "天上火"},   // 己卯年 (lunar) 戊午日
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local), "覆灯火", "路旁土"},   // 甲辰年 庚午日
+		{time.Date(2025, 11, 28, 0, 0, 0, 0, time.Local), "覆灯火", "壁上土"}, // 乙巳年 辛丑日
+	}
+	for _, tt := range tests {
+		view, err := svc.Month(tt.date.Year(), int(tt.date.Month()))
+		if err != nil {
+			t.Fatalf("Month returned error: %v", err)
+		}
+		day := view.Days()[tt.date.Day()-1]
+		if got := day.YearNayin(); got != tt.yearNayin {
+			t.Fatalf("%s: YearNayin=%s want %s", tt.date.Format("2006-01-02"), got, tt.yearNayin)
+		}
+		if got := day.DayNayin(); got != tt.dayNayin {
+			t.Fatalf("%s: DayNayin=%s want %s", tt.date.Format("2006-01-02"), got, tt.dayNayin)
+		}
+	}
+	if got := nayin(59); got != "大海水" {
+		t.Fatalf("nayin(59)=%s want 大海水", got)
+	}
+}
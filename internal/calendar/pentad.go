@@ -0,0 +1,86 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/Lofanmi/chinese-calendar-golang/solarterm"
+)
+
+// pentadNames lists the 七十二候, three per solar term, starting with the
+// first 候 of 立春.
+var pentadNames = []string{
+	"东风解冻", "蛰虫始振", "鱼陟负冰", // 立春
+	"獭祭鱼", "候雁北", "草木萌动", // 雨水
+	"桃始华", "仓庚鸣", "鹰化为鸠", // 惊蛰
+	"玄鸟至", "雷乃发声", "始电", // 春分
+	"桐始华", "田鼠化为鴽", "虹始见", // 清明
+	"萍始生", "鸣鸠拂其羽", "戴胜降于桑", // 谷雨
+	"蝼蝈鸣", "蚯蚓出", "王瓜生", // 立夏
+	"苦菜秀", "靡草死", "麦秋至", // 小满
+	"螳螂生", "䴗始鸣", "反舌无声", // 芒种
+	"鹿角解", "蜩始鸣", "半夏生", // 夏至
+	"温风至", "蟋蟀居壁", "鹰始挚", // 小暑
+	"腐草为萤", "土润溽暑", "大雨时行", // 大暑
+	"凉风至", "白露降", "寒蝉鸣", // 立秋
+	"鹰乃祭鸟", "天地始肃", "禾乃登", // 处暑
+	"鸿雁来", "玄鸟归", "群鸟养羞", // 白露
+	"雷始收声", "蛰虫坯户", "水始涸", // 秋分
+	"鸿雁来宾", "雀入大水为蛤", "菊有黄华", // 寒露
+	"豺乃祭兽", "草木黄落", "蛰虫咸俯", // 霜降
+	"水始冰", "地始冻", "雉入大水为蜃", // 立冬
+	"虹藏不见", "天气上升地气下降", "闭塞而成冬", // 小雪
+	"鹖鴠不鸣", "虎始交", "荔挺出", // 大雪
+	"蚯蚓结", "麋角解", "水泉动", // 冬至
+	"雁北乡", "鹊始巢", "雉始雊", // 小寒
+	"鸡始乳", "征鸟厉疾", "水泽腹坚", // 大寒
+}
+
+// pentadDays is the length of the first two 候 of a solar term; the third
+// runs until the next term.
+const pentadDays = 5
+
+// latestTerm returns the last solar term falling on or before the calendar
+// date of t, read in t's location, or nil before the library's first term.
+func latestTerm(t time.Time) *solarterm.Solarterm {
+	endOfDay := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location()).Add(-time.Second)
+	term, _ := solarterm.CalcSolarterm(&endOfDay)
+	return term
+}
+
+// pentadOf returns the 候 in effect on the calendar date of t, in t's
+// location, and its position in pentadNames, or "" and -1 when no term
+// precedes it. A term falling on a date starts its first 候 that day.
+func pentadOf(t time.Time) (string, int) {
+	if t.Year() < MinSupportedYear || t.Year() > MaxSupportedYear {
+		return "", -1
+	}
+	term := latestTerm(t)
+	if term == nil {
+		return "", -1
+	}
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	start := term.Time().In(t.Location())
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, t.Location())
+	if date.Before(start) {
+		return "", -1 // before the library's first term, 小寒 of 1900
+	}
+	offset := int(date.Sub(start).Hours()/24+0.5) / pentadDays
+	// The library counts terms from 小寒; pentadNames starts at 立春.
+	index := mod(int(term.Index())-2, 24)*3 + min(offset, 2)
+	return pentadNames[index], index
+}
+
+// Pentad returns the 候 of the 七十二候 in effect on the calendar date of t,
+// e.g. 蚯蚓结 in the first five days from 冬至, and its index (0 = 东风解冻,
+// the first 候 of 立春). Outside the supported years, and before 小寒 of
+// 1900, it returns "" and -1.
+func (s *Service) Pentad(t time.Time) (name string, index int) {
+	return pentadOf(t)
+}
+
+// Pentad returns the 候 of the day, e.g. 蚯蚓结, or "" outside the supported
+// years.
+func (d Day) Pentad() string {
+	name, _ := pentadOf(d.Date)
+	return name
+}
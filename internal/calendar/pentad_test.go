@@ -0,0 +1,33 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPentad(t *testing.T) {
+	svc := NewService()
+	tests := []struct {
+		date  time.Time
+		name  string
+		index int
+	}{
+		{time.Date(2025, 2, 3, 0, 0, 0, 0, time.Local), "东风解冻", 0},   // 立春
+		{time.Date(2025, 2, 2, 0, 0, 0, 0, time.Local), "水泽腹坚", 71},  // the day before 立春
+		{time.Date(2025, 12, 21, 0, 0, 0, 0, time.Local), "蚯蚓结", 63}, // 冬至
+		{time.Date(2025, 12, 26, 0, 0, 0, 0, time.Local), "麋角解", 64}, // 冬至 + 5 days
+		{time.Date(2026, 1, 4, 12, 0, 0, 0, time.Local), "水泉动", 65},  // the third 候 runs until 小寒
+		{time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local), "雁北乡", 66},   // 小寒
+		{time.Date(1900, 1, 1, 0, 0, 0, 0, time.Local), "", -1},      // before the first known term
+		{time.Date(MaxSupportedYear+1, 1, 1, 0, 0, 0, 0, time.Local), "", -1},
+	}
+	for _, tt := range tests {
+		name, index := svc.Pentad(tt.date)
+		if name != tt.name || index != tt.index {
+			t.Fatalf("%s: Pentad=%q, %d want %q, %d", tt.date.Format("2006-01-02"), name, index, tt.name, tt.index)
+		}
+	}
+	if len(pentadNames) != 72 {
+		t.Fatalf("len(pentadNames)=%d want 72", len(pentadNames))
+	}
+}
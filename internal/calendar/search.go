@@ -0,0 +1,89 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Lofanmi/chinese-calendar-golang/solarterm"
+
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+// SearchResult is a named day located by FindNext.
+type SearchResult struct {
+	Date time.Time
+	Name string
+}
+
+// Matches reports whether the holiday or solar term of the day has a name
+// containing query, the test FindNext applies. An empty query matches
+// nothing.
+func (d Day) Matches(query string) bool {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return false
+	}
+	if d.HolidayInfo != nil && strings.Contains(d.HolidayInfo.Name, query) {
+		return true
+	}
+	return strings.Contains(d.SolarTerm, query)
+}
+
+// FindNext returns the earliest holiday (from the loaded holiday data) or
+// solar term on or after from whose name contains query.
+func (s *Service) FindNext(query string, from time.Time) (SearchResult, bool) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return SearchResult{}, false
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+
+	var best SearchResult
+	found := false
+	consider := func(date time.Time, name string) {
+		if !found || date.Before(best.Date) {
+			best = SearchResult{Date: date, Name: name}
+			found = true
+		}
+	}
+
+	data := s.holidays()
+	if coverage := holidays.Coverage(data); coverage != nil {
+		until := time.Date(coverage.MaxYear, time.December, 31, 0, 0, 0, 0, from.Location())
+		for _, h := range holidays.GetHolidaysInRange(data, from, until) {
+			if strings.Contains(h.Name, query) {
+				consider(h.Date, h.Name)
+				break
+			}
+		}
+	}
+
+	if date, name, ok := nextSolarterm(query, from); ok {
+		consider(date, name)
+	}
+	return best, found
+}
+
+// nextSolarterm finds the first solar term on or after from, within a year,
+// whose name contains query.
+func nextSolarterm(query string, from time.Time) (time.Time, string, bool) {
+	if from.Year() < MinSupportedYear || from.Year() > MaxSupportedYear {
+		return time.Time{}, "", false
+	}
+	prev, next := solarterm.CalcSolarterm(&from)
+	candidates := make([]*solarterm.Solarterm, 0, 2)
+	if prev != nil && prev.IsInDay(&from) {
+		candidates = append(candidates, prev)
+	}
+	for term, i := next, 0; term != nil && i < 24; term, i = term.Next(), i+1 {
+		candidates = append(candidates, term)
+	}
+	for _, term := range candidates {
+		if strings.Contains(term.Alias(), query) {
+			t := term.Time().In(from.Location())
+			date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, from.Location())
+			return date, term.Alias(), true
+		}
+	}
+	return time.Time{}, "", false
+}
@@ -0,0 +1,46 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+func TestFindNextHoliday(t *testing.T) {
+	data := map[string]map[string]*holidays.HolidayEntry{
+		"2025": {
+			"10-06": {Holiday: true, Name: "中秋节"},
+			"10-11": {Holiday: false, Name: "国庆节后补班"},
+		},
+		"2026": {
+			"09-25": {Holiday: true, Name: "中秋节"},
+		},
+	}
+	svc := NewService(WithHolidays(data))
+
+	got, ok := svc.FindNext("中秋", time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local))
+	if !ok || got.Name != "中秋节" || got.Date.Format("2006-01-02") != "2025-10-06" {
+		t.Fatalf("expected 2025-10-06 中秋节, got %+v, %v", got, ok)
+	}
+	got, ok = svc.FindNext("中秋", time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local))
+	if !ok || got.Date.Format("2006-01-02") != "2026-09-25" {
+		t.Fatalf("expected next year's 中秋节, got %+v, %v", got, ok)
+	}
+	if _, ok := svc.FindNext("端午", time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)); ok {
+		t.Fatalf("expected no match for a missing name")
+	}
+}
+
+func TestFindNextSolarTerm(t *testing.T) {
+	svc := NewService()
+	got, ok := svc.FindNext("冬至", time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local))
+	if !ok || got.Name != "冬至" || got.Date.Format("2006-01-02") != "2025-12-21" {
+		t.Fatalf("expected 2025-12-21 冬至, got %+v, %v", got, ok)
+	}
+	// 立冬 2025 falls on November 7; searching from that day includes it.
+	got, ok = svc.FindNext("立冬", time.Date(2025, 11, 7, 0, 0, 0, 0, time.Local))
+	if !ok || got.Date.Format("2006-01-02") != "2025-11-07" {
+		t.Fatalf("expected 2025-11-07 立冬, got %+v, %v", got, ok)
+	}
+}
@@ -1,12 +1,15 @@
 package calendar
 
 import (
-	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	calendarlib "github.com/Lofanmi/chinese-calendar-golang/calendar"
+	"github.com/lululau/lucal/internal/almanac"
 	"github.com/lululau/lucal/internal/holidays"
+	"github.com/lululau/lucal/internal/notes"
 )
 
 // Supported Gregorian year range enforced by the upstream library.
@@ -43,40 +46,53 @@ func (r Request) Normalize() Request {
 	return r
 }
 
+// Add offsets the request by the given years and months (either may be
+// negative) and normalizes the result.
+func (r Request) Add(years, months int) Request {
+	r.Year += years
+	r.Month += months
+	return r.Normalize()
+}
+
 // NextMonth moves the request to the following month.
 func (r Request) NextMonth() Request {
-	r.Month++
-	return r.Normalize()
+	return r.Add(0, 1)
 }
 
 // PreviousMonth moves the request to the preceding month.
 func (r Request) PreviousMonth() Request {
-	r.Month--
-	return r.Normalize()
+	return r.Add(0, -1)
 }
 
 // NextYear moves to the following year.
 func (r Request) NextYear() Request {
-	r.Year++
-	return r
+	return r.Add(1, 0)
 }
 
 // PreviousYear moves to the preceding year.
 func (r Request) PreviousYear() Request {
-	r.Year--
-	return r
+	return r.Add(-1, 0)
 }
 
 // Day represents a single Gregorian day with lunar metadata.
 type Day struct {
 	Date            time.Time
 	InMonth         bool
+	LunarYear       int
 	LunarDayAlias   string
 	LunarMonthAlias string
+	IsLeapMonth     bool // the lunar month is a 闰月
 	SolarTerm       string
+	SolarTermTime   time.Time // exact local moment of SolarTerm; zero when unset
 	IsToday         bool
+	IsWeekend       bool // Date falls on one of the service's weekend days (see WithWeekend)
 	hasLunarData    bool
 	HolidayInfo     *holidays.HolidayInfo
+	HolidayBreak    *HolidayBreak // the break a holiday belongs to (see Service.HolidayBreak)
+	Note            string
+	Marked          bool     // Date is one of the service's marked dates (see WithMarks)
+	Yi              []string // 宜, only set when almanac data covers the day
+	Ji              []string // 忌, only set when almanac data covers the day
 }
 
 // SecondaryLabel selects the string that should be rendered beneath the
@@ -87,28 +103,80 @@ func (d Day) SecondaryLabel() string {
 		return d.SolarTerm
 	}
 	if d.LunarDayAlias == "初一" && d.LunarMonthAlias != "" {
-		return d.LunarMonthAlias
+		return d.lunarMonthName()
 	}
 	return d.LunarDayAlias
 }
 
+// lunarMonthName is LunarMonthAlias with the 闰 prefix guaranteed for leap
+// months.
+func (d Day) lunarMonthName() string {
+	if d.IsLeapMonth && !strings.HasPrefix(d.LunarMonthAlias, "闰") {
+		return "闰" + d.LunarMonthAlias
+	}
+	return d.LunarMonthAlias
+}
+
+// LunarDateString composes the lunar month and day, e.g. "九月廿九" or
+// "闰六月初一". Unlike SecondaryLabel it always includes both parts.
+func (d Day) LunarDateString() string {
+	if !d.hasLunarData {
+		return ""
+	}
+	return d.LunarMonthAlias + d.LunarDayAlias
+}
+
+// LunarDateStringWithYear prefixes LunarDateString with the sexagenary name
+// of the lunar year, e.g. "乙巳年九月廿九".
+func (d Day) LunarDateStringWithYear() string {
+	if !d.hasLunarData {
+		return ""
+	}
+	return ganzhiYear(d.LunarYear) + "年" + d.LunarDateString()
+}
+
 // HasLunarData reports whether lunar metadata was successfully calculated.
 func (d Day) HasLunarData() bool {
 	return d.hasLunarData
 }
 
-// MonthView describes a month laid out into ISO weeks.
+// MonthView describes a month laid out into weeks. Each week starts on
+// WeekStart.
 type MonthView struct {
-	Year  int
-	Month time.Month
-	Title string
-	Weeks [][]Day
+	Year      int
+	Month     time.Month
+	Title     string
+	Weeks     [][]Day
+	WeekStart time.Weekday
+}
+
+// Days returns the in-month days in chronological order, skipping the
+// padding days of Weeks.
+func (v MonthView) Days() []Day {
+	days := make([]Day, 0, 31)
+	for _, week := range v.Weeks {
+		for _, day := range week {
+			if day.InMonth {
+				days = append(days, day)
+			}
+		}
+	}
+	return days
 }
 
 // Service materialises month/year views using the upstream lunar calendar.
+// A Service is safe for concurrent use; holiday data and the week start may
+// be swapped with SetHolidays and SetWeekStart while other goroutines render.
 type Service struct {
 	now         func() time.Time
+	loc         *time.Location
+	mu          sync.RWMutex // guards holidayData and weekStart
 	holidayData map[string]map[string]*holidays.HolidayEntry
+	notes       map[string]string
+	marks       map[string]bool // keyed like notes
+	almanac     map[string]almanac.Entry
+	weekStart   time.Weekday
+	weekend     [7]bool // indexed by time.Weekday
 }
 
 // Option configures the Service.
@@ -121,6 +189,17 @@ func WithNow(now func() time.Time) Option {
 	}
 }
 
+// WithLocation sets the time zone the calendar lives in: month dates are
+// midnights there and the clock is read there to decide which day is today.
+// The default, also used for nil, is time.Local.
+func WithLocation(loc *time.Location) Option {
+	return func(s *Service) {
+		if loc != nil {
+			s.loc = loc
+		}
+	}
+}
+
 // WithHolidays sets the holiday data for the service.
 func WithHolidays(data map[string]map[string]*holidays.HolidayEntry) Option {
 	return func(s *Service) {
@@ -128,54 +207,138 @@ func WithHolidays(data map[string]map[string]*holidays.HolidayEntry) Option {
 	}
 }
 
+// WithNotes attaches personal notes keyed by notes.Key (YYYY-MM-DD).
+func WithNotes(data map[string]string) Option {
+	return func(s *Service) {
+		s.notes = data
+	}
+}
+
+// WithMarks highlights dates, e.g. days picked for ad-hoc planning. Only the
+// calendar date of each time matters.
+func WithMarks(dates ...time.Time) Option {
+	return func(s *Service) {
+		s.marks = make(map[string]bool, len(dates))
+		for _, date := range dates {
+			s.marks[notes.Key(date)] = true
+		}
+	}
+}
+
+// WithAlmanac attaches 宜/忌 data keyed by Day.LunarDateStringWithYear.
+func WithAlmanac(data map[string]almanac.Entry) Option {
+	return func(s *Service) {
+		s.almanac = data
+	}
+}
+
+// WithWeekStart sets the first column of every week. The default is Sunday.
+func WithWeekStart(day time.Weekday) Option {
+	return func(s *Service) {
+		s.weekStart = day
+	}
+}
+
+// WithWeekend sets the days of the week that are rest days when no holiday
+// data says otherwise, e.g. Friday and Saturday. The default is Saturday and
+// Sunday; no days means a seven-day work week.
+func WithWeekend(days ...time.Weekday) Option {
+	return func(s *Service) {
+		s.weekend = [7]bool{}
+		for _, day := range days {
+			s.weekend[day%7] = true
+		}
+	}
+}
+
 // NewService constructs a Service.
 func NewService(opts ...Option) *Service {
 	s := &Service{
 		now: time.Now,
+		loc: time.Local,
 	}
+	s.weekend[time.Saturday] = true
+	s.weekend[time.Sunday] = true
 	for _, opt := range opts {
 		opt(s)
 	}
 	return s
 }
 
-// HasHolidayData returns true if the service has holiday data loaded.
+// HasHolidayData returns true if the service has holiday data loaded. Data
+// without entries, such as an empty holiday file, counts as none.
 func (s *Service) HasHolidayData() bool {
-	return s.holidayData != nil && len(s.holidayData) > 0
+	return len(s.holidays()) > 0
 }
 
-var (
-	// ErrYearOutOfRange indicates the requested year is unsupported.
-	ErrYearOutOfRange = fmt.Errorf("year must be between %d and %d", MinSupportedYear, MaxSupportedYear)
-	// ErrInvalidMonth indicates the month is not in the 1..12 range.
-	ErrInvalidMonth = errors.New("month must be between 1 and 12")
-)
+// HolidayCoverage reports the years covered by the loaded holiday data, or
+// nil when none is loaded.
+func (s *Service) HolidayCoverage() *holidays.YearInfo {
+	return holidays.Coverage(s.holidays())
+}
+
+// SetHolidays replaces the holiday data. Views built afterwards use the new
+// data; views already being built keep a consistent snapshot per day.
+func (s *Service) SetHolidays(data map[string]map[string]*holidays.HolidayEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.holidayData = data
+}
+
+// SetWeekStart changes the first column of the weeks of views built
+// afterwards, like WithWeekStart.
+func (s *Service) SetWeekStart(day time.Weekday) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.weekStart = day
+}
+
+// WeekStart returns the first column of every week.
+func (s *Service) WeekStart() time.Weekday {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.weekStart
+}
+
+func (s *Service) holidays() map[string]map[string]*holidays.HolidayEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.holidayData
+}
 
 // Month builds a MonthView.
 func (s *Service) Month(year, month int) (MonthView, error) {
-	if year < MinSupportedYear || year > MaxSupportedYear {
-		return MonthView{}, ErrYearOutOfRange
+	return s.MonthAsOf(year, month, s.now())
+}
+
+// MonthAsOf is Month with asOf, rather than the service clock, deciding
+// which day is flagged IsToday. It lets callers render the same month for
+// several "today"s, e.g. frames of a timeline, without rebuilding services.
+func (s *Service) MonthAsOf(year, month int, asOf time.Time) (MonthView, error) {
+	if err := checkYear(year); err != nil {
+		return MonthView{}, err
 	}
 	if month < 1 || month > 12 {
-		return MonthView{}, ErrInvalidMonth
+		return MonthView{}, &MonthError{Month: month}
 	}
 
-	firstDay := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
-	start := firstDay.AddDate(0, 0, -int(firstDay.Weekday()))
+	firstDay := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, s.loc)
+	asOf = asOf.In(s.loc)
+	weekStart := s.WeekStart()
+	start := firstDay.AddDate(0, 0, -mod(int(firstDay.Weekday())-int(weekStart), 7))
 	end := firstDay.AddDate(0, 1, 0)
-	now := s.now()
 
 	weeks := make([][]Day, 0, 6)
 	cursor := start
 	for {
 		week := make([]Day, 7)
 		for i := 0; i < 7; i++ {
-			week[i] = s.buildDay(cursor, firstDay.Month(), now)
+			week[i] = s.buildDay(cursor, firstDay.Month(), asOf)
 			cursor = cursor.AddDate(0, 0, 1)
 		}
 		weeks = append(weeks, week)
 
-		if (cursor.Equal(end) || cursor.After(end)) && cursor.Weekday() == time.Sunday {
+		if (cursor.Equal(end) || cursor.After(end)) && cursor.Weekday() == weekStart {
 			break
 		}
 		// Safety to avoid infinite loops.
@@ -185,39 +348,140 @@ func (s *Service) Month(year, month int) (MonthView, error) {
 	}
 
 	view := MonthView{
-		Year:  year,
-		Month: firstDay.Month(),
-		Title: fmt.Sprintf("%d 年 %d 月", year, month),
-		Weeks: weeks,
+		Year:      year,
+		Month:     firstDay.Month(),
+		Title:     fmt.Sprintf("%d 年 %d 月", year, month),
+		Weeks:     weeks,
+		WeekStart: weekStart,
 	}
 	return view, nil
 }
 
 // Year returns the MonthView list for an entire year.
 func (s *Service) Year(year int) ([]MonthView, error) {
-	if year < MinSupportedYear || year > MaxSupportedYear {
-		return nil, ErrYearOutOfRange
+	if err := checkYear(year); err != nil {
+		return nil, err
 	}
-	months := make([]MonthView, 0, 12)
-	for m := 1; m <= 12; m++ {
-		view, err := s.Month(year, m)
+	return s.Months(year, 1, 12)
+}
+
+// Months returns count consecutive months starting at year/month, rolling
+// into the following years as needed; Months(2025, 4, 12) is the fiscal
+// year April 2025 - March 2026.
+func (s *Service) Months(year, month, count int) ([]MonthView, error) {
+	if month < 1 || month > 12 {
+		return nil, &MonthError{Month: month}
+	}
+	months := make([]MonthView, 0, count)
+	req := Request{Year: year, Month: month}
+	for i := 0; i < count; i++ {
+		view, err := s.Month(req.Year, req.Month)
 		if err != nil {
 			return nil, err
 		}
 		months = append(months, view)
+		req = req.NextMonth()
 	}
 	return months, nil
 }
 
+// IsWorkingDay reports whether t is a working day under the service's
+// weekend days and holiday data; reason explains the determination.
+func (s *Service) IsWorkingDay(t time.Time) (working bool, reason string) {
+	return holidays.WorkingDay(s.holidays(), t, s.weekend[t.Weekday()])
+}
+
+// Now reports the current time of the service clock (see WithNow) in the
+// service location.
+func (s *Service) Now() time.Time {
+	return s.now().In(s.loc)
+}
+
+// Location reports the time zone of the calendar (see WithLocation).
+func (s *Service) Location() *time.Location {
+	return s.loc
+}
+
+// LunarMonthStart is a Gregorian date that is the first day (初一) of a
+// lunar month.
+type LunarMonthStart struct {
+	Date      time.Time
+	MonthName string // e.g. "六月" or "闰六月"
+	Leap      bool
+}
+
+// LunarMonthStarts returns every 初一 that falls in the Gregorian year, in
+// chronological order.
+func (s *Service) LunarMonthStarts(year int) ([]LunarMonthStart, error) {
+	views, err := s.Year(year)
+	if err != nil {
+		return nil, err
+	}
+	starts := make([]LunarMonthStart, 0, 13)
+	for _, view := range views {
+		for _, day := range view.Days() {
+			if day.LunarDayAlias != "初一" {
+				continue
+			}
+			starts = append(starts, LunarMonthStart{
+				Date:      day.Date,
+				MonthName: day.lunarMonthName(),
+				Leap:      day.IsLeapMonth,
+			})
+		}
+	}
+	return starts, nil
+}
+
+// SolarTerm is one of the 24 solar terms (节气) of a year.
+type SolarTerm struct {
+	Date time.Time // local midnight of the day the term falls on
+	Name string    // e.g. "冬至"
+	Time time.Time // exact local moment of the term
+}
+
+// SolarTerms returns the solar terms that fall in the Gregorian year, in
+// chronological order.
+func (s *Service) SolarTerms(year int) ([]SolarTerm, error) {
+	views, err := s.Year(year)
+	if err != nil {
+		return nil, err
+	}
+	terms := make([]SolarTerm, 0, 24)
+	for _, view := range views {
+		for _, day := range view.Days() {
+			if day.SolarTerm == "" {
+				continue
+			}
+			terms = append(terms, SolarTerm{Date: day.Date, Name: day.SolarTerm, Time: day.SolarTermTime})
+		}
+	}
+	return terms, nil
+}
+
+// Today returns the Day for the current date of the service clock, enriched
+// exactly like the days of Month.
+func (s *Service) Today() Day {
+	now := s.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.loc)
+	return s.buildDay(today, today.Month(), now)
+}
+
 func (s *Service) buildDay(day time.Time, currentMonth time.Month, now time.Time) Day {
 	inMonth := day.Month() == currentMonth
 	isToday := sameDay(day, now)
 
+	note := s.notes[notes.Key(day)]
+	marked := s.marks[notes.Key(day)]
+
 	if day.Year() < MinSupportedYear || day.Year() > MaxSupportedYear {
 		return Day{
-			Date:    day,
-			InMonth: inMonth,
-			IsToday: isToday,
+			Date:      day,
+			InMonth:   inMonth,
+			IsToday:   isToday,
+			IsWeekend: s.weekend[day.Weekday()],
+			Note:      note,
+			Marked:    marked,
 		}
 	}
 
@@ -230,23 +494,51 @@ func (s *Service) buildDay(day time.Time, currentMonth time.Month, now time.Time
 	dayData := Day{
 		Date:            day,
 		InMonth:         inMonth,
+		LunarYear:       int(cal.Lunar.GetYear()),
 		LunarDayAlias:   cal.Lunar.DayAlias(),
 		LunarMonthAlias: cal.Lunar.MonthAlias(),
+		IsLeapMonth:     cal.Lunar.IsLeapMonth(),
 		IsToday:         isToday,
+		IsWeekend:       s.weekend[day.Weekday()],
 		hasLunarData:    true,
+		Note:            note,
+		Marked:          marked,
 	}
-	if solarterm := cal.Solar.CurrentSolarterm; solarterm != nil {
-		if solarterm.IsInDay(&day) {
-			dayData.SolarTerm = solarterm.Alias()
-		}
+	// The term is matched in the day's location rather than time.Local, so
+	// that WithLocation moves terms with the dates.
+	if term := latestTerm(day); term != nil && sameDay(term.Time().In(day.Location()), day) {
+		dayData.SolarTerm = term.Alias()
+		dayData.SolarTermTime = term.Time()
+	}
+	if entry, ok := s.almanac[dayData.LunarDateStringWithYear()]; ok {
+		dayData.Yi = entry.Yi
+		dayData.Ji = entry.Ji
 	}
 	// Add holiday information if available
-	if s.holidayData != nil {
-		dayData.HolidayInfo = holidays.GetHolidayForDate(s.holidayData, day.Year(), int(day.Month()), day.Day())
+	if data := s.holidays(); data != nil {
+		dayData.HolidayInfo = holidays.GetHolidayForDate(data, day.Year(), int(day.Month()), day.Day())
+		if b, ok := s.HolidayBreak(day); ok {
+			dayData.HolidayBreak = &b
+		}
 	}
 	return dayData
 }
 
+var (
+	heavenlyStems   = []string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+	earthlyBranches = []string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+)
+
+// ganzhiYear returns the sexagenary (干支) name of a lunar year.
+func ganzhiYear(year int) string {
+	offset := year - 4 // 4 AD is a 甲子 year.
+	return heavenlyStems[mod(offset, 10)] + earthlyBranches[mod(offset, 12)]
+}
+
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
+
 func sameDay(a, b time.Time) bool {
 	y1, m1, d1 := a.Date()
 	y2, m2, d2 := b.Date()
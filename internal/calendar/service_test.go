@@ -1,8 +1,12 @@
 package calendar
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/lululau/lucal/internal/holidays"
+	"github.com/lululau/lucal/internal/holidays/holidaystest"
 )
 
 func TestMonthGeneratesCompleteWeeks(t *testing.T) {
@@ -41,6 +45,54 @@ func TestMonthGeneratesCompleteWeeks(t *testing.T) {
 	}
 }
 
+func TestMonthWithWeekStartMonday(t *testing.T) {
+	svc := NewService(WithWeekStart(time.Monday))
+	// November 2025 starts on a Saturday and ends on a Sunday.
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month returned error: %v", err)
+	}
+	if view.WeekStart != time.Monday {
+		t.Fatalf("expected WeekStart Monday, got %v", view.WeekStart)
+	}
+	first := view.Weeks[0][0].Date
+	if first.Weekday() != time.Monday || first.Day() != 27 {
+		t.Fatalf("expected first cell Monday October 27, got %v", first)
+	}
+	last := view.Weeks[len(view.Weeks)-1]
+	if end := last[6].Date; end.Month() != time.November || end.Day() != 30 {
+		t.Fatalf("expected last cell November 30, got %v", end)
+	}
+}
+
+func TestRequestAdd(t *testing.T) {
+	tests := []struct {
+		name          string
+		start         Request
+		years, months int
+		want          Request
+	}{
+		{"forward within year", Request{Year: 2025, Month: 3}, 0, 3, Request{Year: 2025, Month: 6}},
+		{"forward across year", Request{Year: 2025, Month: 11}, 0, 3, Request{Year: 2026, Month: 2}},
+		{"backward across year", Request{Year: 2025, Month: 2}, 0, -3, Request{Year: 2024, Month: 11}},
+		{"many months back", Request{Year: 2025, Month: 1}, 0, -25, Request{Year: 2022, Month: 12}},
+		{"years and months", Request{Year: 2025, Month: 12, Mode: ModeYear}, -1, 1, Request{Year: 2025, Month: 1, Mode: ModeYear}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.start.Add(tt.years, tt.months); got != tt.want {
+				t.Fatalf("Add(%d, %d)=%+v want %+v", tt.years, tt.months, got, tt.want)
+			}
+		})
+	}
+	if got := (Request{Year: 2025, Month: 12}).NextMonth(); got != (Request{Year: 2026, Month: 1}) {
+		t.Fatalf("NextMonth()=%+v", got)
+	}
+	if got := (Request{Year: 2025, Month: 1}).PreviousMonth(); got != (Request{Year: 2024, Month: 12}) {
+		t.Fatalf("PreviousMonth()=%+v", got)
+	}
+}
+
 func TestYearLoadsAllMonths(t *testing.T) {
 	svc := NewService()
 	months, err := svc.Year(2024)
@@ -52,10 +104,362 @@ func TestYearLoadsAllMonths(t *testing.T) {
 	}
 }
 
+func TestMonthsRollsIntoNextYear(t *testing.T) {
+	svc := NewService()
+	months, err := svc.Months(2025, 4, 12)
+	if err != nil {
+		t.Fatalf("Months returned error: %v", err)
+	}
+	if len(months) != 12 {
+		t.Fatalf("expected 12 months, got %d", len(months))
+	}
+	first, last := months[0], months[len(months)-1]
+	if first.Year != 2025 || first.Month != time.April || last.Year != 2026 || last.Month != time.March {
+		t.Fatalf("expected April 2025 through March 2026, got %s through %s", first.Title, last.Title)
+	}
+	if _, err := svc.Months(2025, 13, 12); !errors.Is(err, ErrInvalidMonth) {
+		t.Fatalf("expected ErrInvalidMonth for month 13, got %v", err)
+	}
+}
+
+func TestMonthViewDays(t *testing.T) {
+	svc := NewService()
+	tests := []struct {
+		year, month, want int
+	}{
+		{2025, 2, 28},
+		{2024, 2, 29},
+		{2025, 4, 30},
+		{2025, 11, 30},
+		{2025, 12, 31},
+	}
+	for _, tt := range tests {
+		view, err := svc.Month(tt.year, tt.month)
+		if err != nil {
+			t.Fatalf("Month(%d, %d) returned error: %v", tt.year, tt.month, err)
+		}
+		days := view.Days()
+		if len(days) != tt.want {
+			t.Fatalf("%d-%02d: expected %d days, got %d", tt.year, tt.month, tt.want, len(days))
+		}
+		for i, day := range days {
+			if !day.InMonth || day.Date.Day() != i+1 {
+				t.Fatalf("%d-%02d: day %d out of order: %v", tt.year, tt.month, i, day.Date)
+			}
+		}
+	}
+}
+
+func TestToday(t *testing.T) {
+	now := time.Date(2025, 10, 1, 22, 30, 0, 0, time.Local)
+	svc := NewService(WithNow(func() time.Time { return now }), WithHolidays(holidaystest.Data()))
+	day := svc.Today()
+	if !day.IsToday || !day.InMonth {
+		t.Fatalf("expected today in month, got IsToday=%v InMonth=%v", day.IsToday, day.InMonth)
+	}
+	if y, m, d := day.Date.Date(); y != 2025 || m != time.October || d != 1 || day.Date.Hour() != 0 {
+		t.Fatalf("expected midnight of 2025-10-01, got %v", day.Date)
+	}
+	if !day.HasLunarData() || day.LunarDateString() != "八月初十" {
+		t.Fatalf("expected lunar date 八月初十, got %q", day.LunarDateString())
+	}
+	if day.HolidayInfo == nil || day.HolidayInfo.Name != "国庆节" {
+		t.Fatalf("expected holiday info for 国庆节, got %+v", day.HolidayInfo)
+	}
+}
+
+func TestLocationDecidesToday(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// 2025-09-30 18:00 UTC is already 10-01 02:00 in Shanghai.
+	now := time.Date(2025, 9, 30, 18, 0, 0, 0, time.UTC)
+	svc := NewService(WithNow(func() time.Time { return now }), WithLocation(shanghai))
+	if day := svc.Today(); day.Date.Month() != time.October || day.Date.Day() != 1 || day.Date.Location() != shanghai {
+		t.Fatalf("expected 2025-10-01 in Shanghai, got %v", day.Date)
+	}
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month returned error: %v", err)
+	}
+	for _, day := range view.Days() {
+		if want := day.Date.Day() == 1; day.IsToday != want {
+			t.Fatalf("day %d: IsToday=%v want %v", day.Date.Day(), day.IsToday, want)
+		}
+	}
+	if utc := NewService(WithNow(func() time.Time { return now }), WithLocation(time.UTC)); utc.Today().Date.Day() != 30 {
+		t.Fatalf("expected 09-30 in UTC, got %v", utc.Today().Date)
+	}
+}
+
+func TestLocationMovesSolarTermsAndPentads(t *testing.T) {
+	// 25 hours apart, so every moment falls on different dates in the two.
+	east, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	west, err := time.LoadLocation("Pacific/Pago_Pago")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	winterSolstice := func(loc *time.Location) Day {
+		view, err := NewService(WithLocation(loc)).Month(2025, 12)
+		if err != nil {
+			t.Fatalf("Month returned error: %v", err)
+		}
+		days := view.Days()
+		for i, day := range days {
+			if day.SolarTerm == "冬至" {
+				if day.Pentad() != "蚯蚓结" || days[i-1].Pentad() == "蚯蚓结" {
+					t.Fatalf("%s: expected 蚯蚓结 to start on 冬至 %s, got %q after %q", loc, day.Date.Format("01-02"), day.Pentad(), days[i-1].Pentad())
+				}
+				return day
+			}
+		}
+		t.Fatalf("%s: no 冬至 in December 2025", loc)
+		return Day{}
+	}
+	if e, w := winterSolstice(east), winterSolstice(west); e.Date.Day() != w.Date.Day()+1 {
+		t.Fatalf("expected 冬至 a day later east of the date line, got %s and %s", e.Date.Format("01-02"), w.Date.Format("01-02"))
+	}
+}
+
+func TestFridaySaturdayWeekend(t *testing.T) {
+	svc := NewService(WithWeekend(time.Friday, time.Saturday), WithHolidays(holidaystest.Data()))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month returned error: %v", err)
+	}
+	days := view.Days()
+	// 2025-10-17 is a Friday, 10-18 a Saturday and 10-19 a Sunday.
+	if !days[16].IsWeekend || !days[17].IsWeekend || days[18].IsWeekend {
+		t.Fatalf("expected Friday and Saturday as the weekend, got Fri=%v Sat=%v Sun=%v",
+			days[16].IsWeekend, days[17].IsWeekend, days[18].IsWeekend)
+	}
+
+	tests := []struct {
+		date        time.Time
+		wantWorking bool
+		wantReason  string
+	}{
+		{time.Date(2025, 10, 17, 0, 0, 0, 0, time.Local), false, "周末"},
+		{time.Date(2025, 10, 19, 0, 0, 0, 0, time.Local), true, "普通工作日"},
+		{time.Date(2025, 10, 3, 0, 0, 0, 0, time.Local), false, "法定假日：国庆节"},
+		{time.Date(2025, 10, 11, 0, 0, 0, 0, time.Local), true, "周末调休上班"},
+	}
+	for _, tt := range tests {
+		working, reason := svc.IsWorkingDay(tt.date)
+		if working != tt.wantWorking || reason != tt.wantReason {
+			t.Fatalf("IsWorkingDay(%s)=(%v, %q) want (%v, %q)",
+				tt.date.Format("2006-01-02"), working, reason, tt.wantWorking, tt.wantReason)
+		}
+	}
+}
+
+func TestLunarMonthStarts(t *testing.T) {
+	svc := NewService()
+	starts, err := svc.LunarMonthStarts(2025)
+	if err != nil {
+		t.Fatalf("LunarMonthStarts failed: %v", err)
+	}
+	// 2025 has a leap sixth month starting on July 25.
+	if len(starts) != 12 {
+		t.Fatalf("expected 12 month starts in 2025, got %d: %+v", len(starts), starts)
+	}
+	if first := starts[0]; first.Date.Format("2006-01-02") != "2025-01-29" || first.Leap {
+		t.Fatalf("unexpected first start %+v", first)
+	}
+	leap := starts[6]
+	if leap.Date.Format("2006-01-02") != "2025-07-25" || !leap.Leap || leap.MonthName != "闰六月" {
+		t.Fatalf("expected 闰六月 on 2025-07-25, got %+v", leap)
+	}
+	for _, start := range starts {
+		if start.Leap != (start == leap) {
+			t.Fatalf("only 闰六月 should be a leap month, got %+v", start)
+		}
+	}
+	if _, err := svc.LunarMonthStarts(1899); !errors.Is(err, ErrYearOutOfRange) {
+		t.Fatalf("expected ErrYearOutOfRange for 1899, got %v", err)
+	}
+}
+
+func TestSolarTerms(t *testing.T) {
+	svc := NewService()
+	terms, err := svc.SolarTerms(2025)
+	if err != nil {
+		t.Fatalf("SolarTerms returned error: %v", err)
+	}
+	if len(terms) != 24 {
+		t.Fatalf("expected 24 solar terms, got %d", len(terms))
+	}
+	if first := terms[0]; first.Name != "小寒" || first.Date.Month() != time.January || first.Date.Day() != 5 {
+		t.Fatalf("expected 小寒 on 2025-01-05 first, got %s on %s", first.Name, first.Date.Format("2006-01-02"))
+	}
+	if last := terms[23]; last.Name != "冬至" || last.Date.Day() != 21 {
+		t.Fatalf("expected 冬至 on 2025-12-21 last, got %s on %s", last.Name, last.Date.Format("2006-01-02"))
+	}
+}
+
 func TestInvalidMonth(t *testing.T) {
 	svc := NewService()
-	if _, err := svc.Month(2024, 13); err == nil {
-		t.Fatalf("expected error for invalid month")
+	_, err := svc.Month(2024, 13)
+	var monthErr *MonthError
+	if !errors.As(err, &monthErr) || monthErr.Month != 13 || !errors.Is(err, ErrInvalidMonth) {
+		t.Fatalf("expected MonthError for month 13, got %v", err)
+	}
+}
+
+func TestYearRangeError(t *testing.T) {
+	svc := NewService()
+	for _, call := range []func() error{
+		func() error { _, err := svc.Month(3001, 1); return err },
+		func() error { _, err := svc.Year(3001); return err },
+	} {
+		err := call()
+		var rangeErr *YearRangeError
+		if !errors.As(err, &rangeErr) || rangeErr.Year != 3001 || rangeErr.Max != MaxSupportedYear {
+			t.Fatalf("expected YearRangeError for 3001, got %v", err)
+		}
+		if !errors.Is(err, ErrYearOutOfRange) {
+			t.Fatalf("expected errors.Is(ErrYearOutOfRange) for %v", err)
+		}
+	}
+}
+
+func findDay(t *testing.T, view MonthView, dayOfMonth int) Day {
+	t.Helper()
+	for _, week := range view.Weeks {
+		for _, day := range week {
+			if day.InMonth && day.Date.Day() == dayOfMonth {
+				return day
+			}
+		}
+	}
+	t.Fatalf("day %d not found in %s", dayOfMonth, view.Title)
+	return Day{}
+}
+
+func TestLunarDateStringLeapMonth(t *testing.T) {
+	svc := NewService()
+	// 2023-03-22 is 闰二月初一.
+	view, err := svc.Month(2023, 3)
+	if err != nil {
+		t.Fatalf("Month returned error: %v", err)
+	}
+	day := findDay(t, view, 22)
+	if got := day.LunarDateString(); got != "闰二月初一" {
+		t.Fatalf("LunarDateString()=%q want %q", got, "闰二月初一")
+	}
+	if got := day.LunarDateStringWithYear(); got != "癸卯年闰二月初一" {
+		t.Fatalf("LunarDateStringWithYear()=%q want %q", got, "癸卯年闰二月初一")
+	}
+	if !day.IsLeapMonth || day.SecondaryLabel() != "闰二月" {
+		t.Fatalf("expected leap month label 闰二月, got leap=%v label=%q", day.IsLeapMonth, day.SecondaryLabel())
+	}
+	if before := findDay(t, view, 21); before.IsLeapMonth {
+		t.Fatalf("2023-03-21 is still 二月, expected IsLeapMonth=false")
 	}
 }
 
+func TestLunarDateStringFirstDayOfMonth(t *testing.T) {
+	svc := NewService()
+	// 2025-11-20 is 十月初一; SecondaryLabel shows only the month name.
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month returned error: %v", err)
+	}
+	day := findDay(t, view, 20)
+	if got := day.SecondaryLabel(); got != "十月" {
+		t.Fatalf("SecondaryLabel()=%q want %q", got, "十月")
+	}
+	if got := day.LunarDateString(); got != "十月初一" {
+		t.Fatalf("LunarDateString()=%q want %q", got, "十月初一")
+	}
+	if got := day.LunarDateStringWithYear(); got != "乙巳年十月初一" {
+		t.Fatalf("LunarDateStringWithYear()=%q want %q", got, "乙巳年十月初一")
+	}
+}
+
+func TestSolarTermTime(t *testing.T) {
+	svc := NewService()
+	view, err := svc.Month(2025, 12)
+	if err != nil {
+		t.Fatalf("Month returned error: %v", err)
+	}
+	day := findDay(t, view, 21)
+	if day.SolarTerm != "冬至" {
+		t.Fatalf("expected 冬至 on 2025-12-21, got %q", day.SolarTerm)
+	}
+	y, m, d := day.SolarTermTime.Date()
+	if y != 2025 || m != time.December || d != 21 {
+		t.Fatalf("expected SolarTermTime on 2025-12-21, got %v", day.SolarTermTime)
+	}
+	if next := findDay(t, view, 22); !next.SolarTermTime.IsZero() {
+		t.Fatalf("expected no SolarTermTime on a day without a term, got %v", next.SolarTermTime)
+	}
+}
+
+func TestSetHolidaysConcurrentWithRendering(t *testing.T) {
+	dataA := map[string]map[string]*holidays.HolidayEntry{
+		"2025": {"10-01": {Holiday: true, Name: "国庆节"}},
+	}
+	dataB := map[string]map[string]*holidays.HolidayEntry{
+		"2025": {"10-11": {Holiday: false, Name: "国庆节后补班"}},
+	}
+	svc := NewService(WithHolidays(dataA))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			if i%2 == 0 {
+				svc.SetHolidays(dataB)
+			} else {
+				svc.SetHolidays(dataA)
+			}
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		if _, err := svc.Month(2025, 10); err != nil {
+			t.Fatalf("Month returned error: %v", err)
+		}
+		svc.HasHolidayData()
+	}
+	<-done
+
+	svc.SetHolidays(dataB)
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month returned error: %v", err)
+	}
+	if info := findDay(t, view, 11).HolidayInfo; info == nil || info.IsHoliday {
+		t.Fatalf("expected swapped data to mark 10-11 as a workday, got %+v", info)
+	}
+	if info := findDay(t, view, 1).HolidayInfo; info != nil {
+		t.Fatalf("expected 10-01 to no longer be a holiday, got %+v", info)
+	}
+}
+
+func TestMonthAsOf(t *testing.T) {
+	now := time.Date(2025, 11, 28, 12, 0, 0, 0, time.Local)
+	svc := NewService(WithNow(func() time.Time { return now }))
+	for _, asOf := range []time.Time{
+		time.Date(2025, 11, 3, 9, 0, 0, 0, time.Local),
+		time.Date(2025, 11, 30, 23, 59, 0, 0, time.Local),
+	} {
+		view, err := svc.MonthAsOf(2025, 11, asOf)
+		if err != nil {
+			t.Fatalf("MonthAsOf returned error: %v", err)
+		}
+		var today []int
+		for _, day := range view.Days() {
+			if day.IsToday {
+				today = append(today, day.Date.Day())
+			}
+		}
+		if len(today) != 1 || today[0] != asOf.Day() {
+			t.Fatalf("expected only day %d flagged today, got %v", asOf.Day(), today)
+		}
+	}
+}
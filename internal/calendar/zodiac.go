@@ -0,0 +1,45 @@
+package calendar
+
+import "time"
+
+// zodiacSign is a Western star sign and the first day it covers.
+type zodiacSign struct {
+	month time.Month
+	day   int
+	name  string
+}
+
+// zodiacSigns lists the signs by start date using the common boundaries;
+// 摩羯座 runs from December 22 into January.
+var zodiacSigns = []zodiacSign{
+	{time.January, 20, "水瓶座"},
+	{time.February, 19, "双鱼座"},
+	{time.March, 21, "白羊座"},
+	{time.April, 20, "金牛座"},
+	{time.May, 21, "双子座"},
+	{time.June, 22, "巨蟹座"},
+	{time.July, 23, "狮子座"},
+	{time.August, 23, "处女座"},
+	{time.September, 23, "天秤座"},
+	{time.October, 24, "天蝎座"},
+	{time.November, 23, "射手座"},
+	{time.December, 22, "摩羯座"},
+}
+
+// WesternZodiac returns the Western star sign (星座) of t's date in Chinese,
+// e.g. 狮子座 for July 23 through August 22.
+func WesternZodiac(t time.Time) string {
+	return zodiacOf(t).name
+}
+
+func zodiacOf(t time.Time) zodiacSign {
+	// Dates before January 20 still belong to the previous December's sign.
+	sign := zodiacSigns[len(zodiacSigns)-1]
+	for _, s := range zodiacSigns {
+		if t.Month() < s.month || (t.Month() == s.month && t.Day() < s.day) {
+			break
+		}
+		sign = s
+	}
+	return sign
+}
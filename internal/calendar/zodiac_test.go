@@ -0,0 +1,44 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWesternZodiacBoundaries(t *testing.T) {
+	tests := []struct {
+		month time.Month
+		day   int
+		name  string
+	}{
+		{time.January, 1, "摩羯座"},
+		{time.January, 19, "摩羯座"},
+		{time.January, 20, "水瓶座"},
+		{time.February, 18, "水瓶座"},
+		{time.February, 19, "双鱼座"},
+		{time.February, 29, "双鱼座"},
+		{time.March, 20, "双鱼座"},
+		{time.March, 21, "白羊座"},
+		{time.April, 20, "金牛座"},
+		{time.May, 21, "双子座"},
+		{time.June, 21, "双子座"},
+		{time.June, 22, "巨蟹座"},
+		{time.July, 22, "巨蟹座"},
+		{time.July, 23, "狮子座"},
+		{time.August, 22, "狮子座"},
+		{time.August, 23, "处女座"},
+		{time.September, 23, "天秤座"},
+		{time.October, 23, "天秤座"},
+		{time.October, 24, "天蝎座"},
+		{time.November, 23, "射手座"},
+		{time.December, 21, "射手座"},
+		{time.December, 22, "摩羯座"},
+		{time.December, 31, "摩羯座"},
+	}
+	for _, tt := range tests {
+		date := time.Date(2024, tt.month, tt.day, 12, 0, 0, 0, time.Local)
+		if got := WesternZodiac(date); got != tt.name {
+			t.Fatalf("WesternZodiac(%s)=%s want %s", date.Format("01-02"), got, tt.name)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package holidays
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CacheFormatVersion is the version stamped on caches written by
+// DownloadHolidays. Plain holiday files, including caches written before
+// versioning, are version 0.
+const CacheFormatVersion = 1
+
+// minCacheFormatVersion is the oldest cache format that is still read as
+// is. Raise it when a schema change makes older caches misparse, so
+// IsCacheValid sends users to lucal -u instead.
+const minCacheFormatVersion = 0
+
+// ErrCacheVersion is returned for caches stamped with a format version this
+// build cannot read, i.e. written by a newer lucal.
+var ErrCacheVersion = errors.New("不支持的节假日缓存格式版本")
+
+// cacheEnvelope is the layout of a versioned cache:
+// {"version": 1, "holidays": [...]}. Version comes first so CacheVersion
+// can read it without decoding the data.
+type cacheEnvelope struct {
+	Version  int             `json:"version"`
+	Holidays json.RawMessage `json:"holidays"`
+}
+
+// unwrapEnvelope returns the holiday data of a versioned cache, rejecting
+// versions newer than CacheFormatVersion.
+func unwrapEnvelope(data []byte) ([]byte, error) {
+	var envelope cacheEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Version > CacheFormatVersion {
+		return nil, fmt.Errorf("%w: %d（当前支持 %d）", ErrCacheVersion, envelope.Version, CacheFormatVersion)
+	}
+	return envelope.Holidays, nil
+}
+
+// CacheVersion returns the format version of the holiday file at path
+// without decoding its data: the stamped version of a versioned cache, and
+// 0 for a plain array or year-keyed object.
+func CacheVersion(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	token, err := decoder.Token()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+	if token != json.Delim('{') {
+		return 0, nil
+	}
+	if key, err := decoder.Token(); err != nil || key != "version" {
+		return 0, nil
+	}
+	var version int
+	if err := decoder.Decode(&version); err != nil {
+		return 0, fmt.Errorf("failed to read cache version: %w", err)
+	}
+	return version, nil
+}
+
+// writeCache stamps the downloaded holiday file at src with
+// CacheFormatVersion and moves it to dest.
+func writeCache(src, dest string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"version": %d, "holidays": `, CacheFormatVersion)
+	buf.Write(bytes.TrimSpace(data))
+	buf.WriteString("}\n")
+
+	tmp := dest + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
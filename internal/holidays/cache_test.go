@@ -0,0 +1,63 @@
+package holidays
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestDownloadStampsCacheVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleHolidayJSON))
+	}))
+	defer server.Close()
+	destPath := filepath.Join(t.TempDir(), "holidays.json")
+	if msg := runModel(t, newDownloadModel([]string{server.URL}, destPath)); msg.err != nil {
+		t.Fatalf("expected success, got %v", msg.err)
+	}
+
+	if version, err := CacheVersion(destPath); err != nil || version != CacheFormatVersion {
+		t.Fatalf("CacheVersion=%d, %v want %d", version, err, CacheFormatVersion)
+	}
+	if valid, err := IsCacheValid(destPath); err != nil || !valid {
+		t.Fatalf("expected the fresh cache to be valid, got %v, %v", valid, err)
+	}
+	data, err := LoadFromFile(destPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	if info := GetHolidayForDate(data, 2025, 10, 1); info == nil || info.Name != "国庆节" {
+		t.Fatalf("expected 国庆节 from the versioned cache, got %+v", info)
+	}
+}
+
+func TestCacheVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		version int
+		valid   bool
+	}{
+		{"plain array", sampleHolidayJSON, 0, true},
+		{"year-keyed object", `{"2025": {"10-01": {"holiday": true, "name": "国庆节"}}}`, 0, true},
+		{"current", `{"version": 1, "holidays": ` + sampleHolidayJSON + `}`, 1, true},
+		{"newer", `{"version": 99, "holidays": ` + sampleHolidayJSON + `}`, 99, false},
+	}
+	for _, tt := range tests {
+		path := writeTempFile(t, tt.content)
+		version, err := CacheVersion(path)
+		if err != nil || version != tt.version {
+			t.Fatalf("%s: CacheVersion=%d, %v want %d", tt.name, version, err, tt.version)
+		}
+		if valid, err := IsCacheValid(path); err != nil || valid != tt.valid {
+			t.Fatalf("%s: IsCacheValid=%v, %v want %v", tt.name, valid, err, tt.valid)
+		}
+	}
+
+	path := writeTempFile(t, `{"version": 99, "holidays": []}`)
+	if _, err := LoadFromFile(path); !errors.Is(err, ErrCacheVersion) {
+		t.Fatalf("expected ErrCacheVersion for a newer cache, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package holidays
+
+import "reflect"
+
+// Diff summarizes how one holiday dataset differs from another, counted by
+// dated entry.
+type Diff struct {
+	Added   int
+	Removed int
+	Changed int
+	Old     *YearInfo // Coverage of the old data; nil when there was none
+	New     *YearInfo // Coverage of the new data
+}
+
+// Empty reports whether the two datasets hold the same entries.
+func (d Diff) Empty() bool {
+	return d.Added == 0 && d.Removed == 0 && d.Changed == 0
+}
+
+// DiffHolidays compares old against new. Entries are matched by year and
+// MM-DD key and compared field by field.
+func DiffHolidays(old, new map[string]map[string]*HolidayEntry) Diff {
+	diff := Diff{Old: Coverage(old), New: Coverage(new)}
+	for year, entries := range new {
+		for key, entry := range entries {
+			previous, ok := old[year][key]
+			switch {
+			case !ok:
+				diff.Added++
+			case !reflect.DeepEqual(previous, entry):
+				diff.Changed++
+			}
+		}
+	}
+	for year, entries := range old {
+		for key := range entries {
+			if _, ok := new[year][key]; !ok {
+				diff.Removed++
+			}
+		}
+	}
+	return diff
+}
@@ -1,24 +1,73 @@
 package holidays
 
 import (
-	"encoding/json"
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
 )
 
 const (
 	holidaysURL = "https://raw.githubusercontent.com/lululau/lucal/main/holidays.json"
+	// autoExitDelay is how long the result screen stays up without a key
+	// press.
+	autoExitDelay = 5 * time.Second
 )
 
+// httpClient is shared by every holiday fetch. The default transport honors
+// HTTP_PROXY/HTTPS_PROXY; the timeout keeps a stalled mirror from hanging.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// DefaultMirrors lists the locations tried, in order, when downloading the
+// holiday data. The jsDelivr mirrors are usually reachable from mainland
+// China when raw.githubusercontent.com is not.
+var DefaultMirrors = []string{
+	holidaysURL,
+	"https://cdn.jsdelivr.net/gh/lululau/lucal@main/holidays.json",
+	"https://fastly.jsdelivr.net/gh/lululau/lucal@main/holidays.json",
+}
+
+// ErrNotJSON is returned when a download is plainly not holiday JSON, such
+// as the HTML page of a captive portal served with HTTP 200.
+var ErrNotJSON = errors.New("下载内容不是有效的 JSON（可能被网络劫持）")
+
+// sniffJSON rejects responses that announce HTML or whose first non-blank
+// byte cannot start a JSON array or object. The returned reader replays the
+// peeked bytes.
+func sniffJSON(resp *http.Response) (io.Reader, error) {
+	contentType := strings.ToLower(resp.Header.Get("Content-Type"))
+	if strings.Contains(contentType, "html") {
+		return nil, fmt.Errorf("%w: Content-Type 为 %s", ErrNotJSON, contentType)
+	}
+	reader := bufio.NewReader(resp.Body)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(head) == 0 || (head[0] != '[' && head[0] != '{') {
+		return nil, fmt.Errorf("%w: 内容以 %q 开头", ErrNotJSON, truncate(head, 16))
+	}
+	return reader, nil
+}
+
+func truncate(b []byte, n int) string {
+	if len(b) > n {
+		b = b[:n]
+	}
+	return string(b)
+}
+
 type downloadProgressMsg struct {
 	bytesDownloaded int64
 	totalBytes      int64
@@ -29,10 +78,16 @@ type downloadCompleteMsg struct {
 	fileSize int64
 	modTime  time.Time
 	filePath string
+	source   string    // Mirror the data was downloaded from
 	yearInfo *YearInfo // Information about years in the downloaded data
+	dryRun   bool      // The download was only compared against the cache
+	diff     *Diff     // Changes against the cache, set for dry runs
 	err      error
 }
 
+// autoExitMsg closes the result screen once autoExitDelay has passed.
+type autoExitMsg struct{}
+
 // YearInfo contains information about the years in the holiday data
 type YearInfo struct {
 	MinYear int // Earliest year
@@ -41,7 +96,7 @@ type YearInfo struct {
 }
 
 type downloadModel struct {
-	url        string
+	urls       []string
 	destPath   string
 	downloaded int64
 	total      int64
@@ -51,15 +106,21 @@ type downloadModel struct {
 	fileSize   int64
 	modTime    time.Time
 	filePath   string
+	source     string
 	yearInfo   *YearInfo
+	dryRun     bool
+	diff       *Diff
 	progressCh chan downloadProgressMsg
 	completeCh chan downloadCompleteMsg
 	waitingKey bool // Whether we're waiting for user to press a key after completion
+	// interactive keeps the result on screen until a key press or
+	// autoExitDelay; otherwise the program quits as soon as it is done.
+	interactive bool
 }
 
-func newDownloadModel(url, destPath string) downloadModel {
+func newDownloadModel(urls []string, destPath string) downloadModel {
 	return downloadModel{
-		url:        url,
+		urls:       urls,
 		destPath:   destPath,
 		progressCh: make(chan downloadProgressMsg, 10),
 		completeCh: make(chan downloadCompleteMsg, 1),
@@ -83,100 +144,148 @@ func (m downloadModel) listenProgress() tea.Msg {
 }
 
 func (m downloadModel) startDownload() tea.Msg {
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(m.destPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	// Create directory if it doesn't exist. Dry runs stage the download in
+	// the system temp directory so the cache directory is left untouched.
+	tmpPath := m.destPath + ".download"
+	if m.dryRun {
+		tmpPath = filepath.Join(os.TempDir(), fmt.Sprintf("lucal-holidays-%d.json", os.Getpid()))
+	} else if err := os.MkdirAll(filepath.Dir(m.destPath), 0755); err != nil {
 		m.completeCh <- downloadCompleteMsg{err: fmt.Errorf("failed to create directory: %w", err)}
 		return nil
 	}
 
-	// Start download in goroutine
+	// Start download in goroutine, trying each mirror in turn. Each attempt
+	// goes to a temporary file that only replaces the cache once it parses,
+	// so a failed or truncated download never clobbers good data.
 	go func() {
-		// Start HTTP request
-		resp, err := http.Get(m.url)
-		if err != nil {
-			m.completeCh <- downloadCompleteMsg{err: fmt.Errorf("failed to start download: %w", err)}
-			return
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			m.completeCh <- downloadCompleteMsg{err: fmt.Errorf("HTTP %d %s", resp.StatusCode, resp.Status)}
-			return
-		}
-
-		totalBytes := resp.ContentLength
+		defer os.Remove(tmpPath)
+		var failures []string
+		for _, url := range m.urls {
+			if err := m.fetch(url, tmpPath); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", url, err))
+				continue
+			}
+			data, _, err := Load(tmpPath)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", url, err))
+				continue
+			}
 
-		// Create destination file
-		file, err := os.Create(m.destPath)
-		if err != nil {
-			m.completeCh <- downloadCompleteMsg{err: fmt.Errorf("failed to create file: %w", err)}
-			return
-		}
-		defer file.Close()
-
-		// Track download progress
-		var downloaded int64
-		startTime := time.Now()
-
-		// Use TeeReader to track bytes
-		reader := io.TeeReader(resp.Body, &progressWriter{
-			onWrite: func(n int) {
-				atomic.AddInt64(&downloaded, int64(n))
-			},
-		})
-
-		// Send progress updates periodically
-		go func() {
-			ticker := time.NewTicker(100 * time.Millisecond)
-			defer ticker.Stop()
-			for range ticker.C {
-				currentBytes := atomic.LoadInt64(&downloaded)
-				if currentBytes > 0 {
-					elapsed := time.Since(startTime).Seconds()
-					speed := float64(currentBytes) / elapsed
-					select {
-					case m.progressCh <- downloadProgressMsg{
-						bytesDownloaded: currentBytes,
-						totalBytes:      totalBytes,
-						speed:           speed,
-					}:
-					default:
-						// Channel is full, skip this update
-					}
+			if m.dryRun {
+				// A missing or unreadable cache simply diffs as empty.
+				current, _ := LoadFromFile(m.destPath)
+				diff := DiffHolidays(current, data)
+				m.completeCh <- downloadCompleteMsg{
+					filePath: m.destPath,
+					source:   url,
+					yearInfo: diff.New,
+					dryRun:   true,
+					diff:     &diff,
 				}
+				return
 			}
-		}()
 
-		// Copy data
-		_, err = io.Copy(file, reader)
-		if err != nil {
-			m.completeCh <- downloadCompleteMsg{err: fmt.Errorf("failed to write file: %w", err)}
-			return
-		}
+			if err := writeCache(tmpPath, m.destPath); err != nil {
+				m.completeCh <- downloadCompleteMsg{err: fmt.Errorf("failed to replace cache: %w", err)}
+				return
+			}
+			info, err := os.Stat(m.destPath)
+			if err != nil {
+				m.completeCh <- downloadCompleteMsg{err: fmt.Errorf("failed to stat file: %w", err)}
+				return
+			}
 
-		// Get file info
-		info, err := os.Stat(m.destPath)
-		if err != nil {
-			m.completeCh <- downloadCompleteMsg{err: fmt.Errorf("failed to stat file: %w", err)}
+			m.completeCh <- downloadCompleteMsg{
+				fileSize: info.Size(),
+				modTime:  info.ModTime(),
+				filePath: m.destPath,
+				source:   url,
+				yearInfo: Coverage(data),
+			}
 			return
 		}
-
-		// Parse the downloaded file to extract year information
-		yearInfo, err := extractYearInfo(m.destPath)
-		if err != nil {
-			// If we can't parse year info, continue anyway (non-fatal)
-			yearInfo = nil
+		m.completeCh <- downloadCompleteMsg{
+			err: fmt.Errorf("所有镜像均下载失败:\n  %s", strings.Join(failures, "\n  ")),
 		}
+	}()
 
-		m.completeCh <- downloadCompleteMsg{
-			fileSize: info.Size(),
-			modTime:  info.ModTime(),
-			filePath: m.destPath,
-			yearInfo: yearInfo,
+	return nil
+}
+
+// fetch downloads url into path, reporting progress.
+func (m downloadModel) fetch(url, path string) error {
+	// Start HTTP request
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to start download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d %s", resp.StatusCode, resp.Status)
+	}
+
+	totalBytes := resp.ContentLength
+	body, err := sniffJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	// Create destination file
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	// Track download progress
+	var downloaded int64
+	startTime := time.Now()
+
+	// Use TeeReader to track bytes
+	reader := io.TeeReader(body, &progressWriter{
+		onWrite: func(n int) {
+			atomic.AddInt64(&downloaded, int64(n))
+		},
+	})
+
+	// Send progress updates periodically
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			currentBytes := atomic.LoadInt64(&downloaded)
+			if currentBytes > 0 {
+				elapsed := time.Since(startTime).Seconds()
+				speed := float64(currentBytes) / elapsed
+				select {
+				case m.progressCh <- downloadProgressMsg{
+					bytesDownloaded: currentBytes,
+					totalBytes:      totalBytes,
+					speed:           speed,
+				}:
+				default:
+					// Channel is full, skip this update
+				}
+			}
 		}
 	}()
 
+	// Copy data, one byte past the cap so oversized responses are detected
+	n, err := io.CopyN(file, reader, maxFileSize+1)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if n > maxFileSize {
+		return fmt.Errorf("%w: 下载内容超过 %d 字节上限", ErrFileTooLarge, maxFileSize)
+	}
 	return nil
 }
 
@@ -207,10 +316,18 @@ func (m downloadModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.fileSize = msg.fileSize
 		m.modTime = msg.modTime
 		m.filePath = msg.filePath
+		m.source = msg.source
 		m.yearInfo = msg.yearInfo
+		m.diff = msg.diff
+		if !m.interactive {
+			return m, tea.Quit
+		}
+		// Give the user a moment to read the result, then quit on a key
+		// press or once autoExitDelay has passed.
 		m.waitingKey = true
-		// Don't quit immediately, wait for user to see the message and press a key
-		return m, nil
+		return m, tea.Tick(autoExitDelay, func(time.Time) tea.Msg { return autoExitMsg{} })
+	case autoExitMsg:
+		return m, tea.Quit
 	case downloadProgressMsg:
 		m.downloaded = msg.bytesDownloaded
 		m.total = msg.totalBytes
@@ -223,29 +340,11 @@ func (m downloadModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m downloadModel) View() string {
 	if m.done {
+		exitHint := fmt.Sprintf("按任意键退出（%d 秒后自动退出）...\n", int(autoExitDelay/time.Second))
 		if m.err != nil {
-			cachePath := m.destPath
-			errorMsg := fmt.Sprintf("❌ 下载失败\n\n错误详情: %v\n\n", m.err)
-			errorMsg += "您可以手动下载节假日数据文件：\n"
-			errorMsg += fmt.Sprintf("1. 访问: %s\n", holidaysURL)
-			errorMsg += fmt.Sprintf("2. 下载文件并保存到: %s\n", cachePath)
-			errorMsg += "3. 确保目录存在（如果不存在，请先创建目录）\n\n"
-			errorMsg += "按任意键退出...\n"
-			return errorMsg
-		}
-		sizeStr := formatBytes(m.fileSize)
-		timeStr := m.modTime.Format("2006-01-02 15:04:05")
-		successMsg := fmt.Sprintf("✅ 下载成功!\n\n文件大小: %s\n更新时间: %s\n保存位置: %s\n", sizeStr, timeStr, m.filePath)
-
-		// Add year information if available
-		if m.yearInfo != nil {
-			successMsg += fmt.Sprintf("\n数据年份范围: %d 年 - %d 年\n", m.yearInfo.MinYear, m.yearInfo.MaxYear)
-			successMsg += fmt.Sprintf("最新数据年份: %d 年\n", m.yearInfo.MaxYear)
-			successMsg += fmt.Sprintf("总共包含 %d 年的数据\n", m.yearInfo.Count)
+			return fmt.Sprintf("❌ 下载失败\n\n错误详情: %v\n\n", m.err) + m.manualDownloadHelp() + "\n" + exitHint
 		}
-
-		successMsg += "\n按任意键退出...\n"
-		return successMsg
+		return m.resultMessage() + "\n" + exitHint
 	}
 
 	// Custom progress bar
@@ -281,6 +380,34 @@ func (m downloadModel) View() string {
 	return fmt.Sprintf("正在下载节假日数据...\n\n[%s]\n%s\n\n按 Ctrl+C 取消\n", progressBar, progressInfo)
 }
 
+// resultMessage describes a successful download or dry run. It is shown on
+// the result screen and printed again once the program exits.
+func (m downloadModel) resultMessage() string {
+	if m.dryRun && m.diff != nil {
+		return dryRunSummary(m.source, m.filePath, *m.diff)
+	}
+	sizeStr := formatBytes(m.fileSize)
+	timeStr := m.modTime.Format("2006-01-02 15:04:05")
+	successMsg := fmt.Sprintf("✅ 下载成功!\n\n下载来源: %s\n文件大小: %s\n更新时间: %s\n保存位置: %s\n", m.source, sizeStr, timeStr, m.filePath)
+
+	// Add year information if available
+	if m.yearInfo != nil {
+		successMsg += fmt.Sprintf("\n数据年份范围: %d 年 - %d 年\n", m.yearInfo.MinYear, m.yearInfo.MaxYear)
+		successMsg += fmt.Sprintf("最新数据年份: %d 年\n", m.yearInfo.MaxYear)
+		successMsg += fmt.Sprintf("总共包含 %d 年的数据\n", m.yearInfo.Count)
+	}
+	return successMsg
+}
+
+// manualDownloadHelp explains how to fetch the data by hand after a failure.
+func (m downloadModel) manualDownloadHelp() string {
+	help := "您可以手动下载节假日数据文件：\n"
+	help += fmt.Sprintf("1. 访问: %s\n", holidaysURL)
+	help += fmt.Sprintf("2. 下载文件并保存到: %s\n", m.destPath)
+	help += "3. 确保目录存在（如果不存在，请先创建目录）\n"
+	return help
+}
+
 func formatBytes(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -298,71 +425,86 @@ func formatSpeed(speed float64) string {
 	return fmt.Sprintf("%s/s", formatBytes(int64(speed)))
 }
 
-// extractYearInfo parses the holiday JSON file and extracts year information
-func extractYearInfo(filePath string) (*YearInfo, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
-	}
+// DownloadHolidays downloads the holidays JSON file and saves it to the cache
+// directory. The given mirrors are tried in order until one succeeds; with no
+// arguments DefaultMirrors is used.
+func DownloadHolidays(mirrors ...string) error {
+	return runDownloader(false, mirrors)
+}
 
-	var holidayData HolidayData
-	if err := json.Unmarshal(data, &holidayData); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
-	}
+// DryRunDownload fetches and validates the holiday data like
+// DownloadHolidays, then reports how it differs from the cache and discards
+// it. The cache is never modified.
+func DryRunDownload(mirrors ...string) error {
+	return runDownloader(true, mirrors)
+}
 
-	if len(holidayData) == 0 {
-		return nil, fmt.Errorf("no year data found")
+// dryRunSummary describes the result of a dry run.
+func dryRunSummary(source, cachePath string, diff Diff) string {
+	var sb strings.Builder
+	sb.WriteString("🔍 预览完成（--dry-run），缓存未做任何修改\n\n")
+	fmt.Fprintf(&sb, "下载来源: %s\n缓存位置: %s\n", source, cachePath)
+	if diff.Old != nil {
+		fmt.Fprintf(&sb, "\n当前缓存年份范围: %d 年 - %d 年\n", diff.Old.MinYear, diff.Old.MaxYear)
+	} else {
+		sb.WriteString("\n当前没有可用的缓存\n")
 	}
-
-	// Extract all years and convert to integers
-	years := make([]int, 0, len(holidayData))
-	for _, yearData := range holidayData {
-		year, err := strconv.Atoi(yearData.Year)
-		if err != nil {
-			continue // Skip invalid years
-		}
-		years = append(years, year)
+	if diff.New != nil {
+		fmt.Fprintf(&sb, "远程数据年份范围: %d 年 - %d 年\n", diff.New.MinYear, diff.New.MaxYear)
 	}
-
-	if len(years) == 0 {
-		return nil, fmt.Errorf("no valid years found")
+	if diff.Empty() {
+		sb.WriteString("\n远程数据与缓存一致，无需更新\n")
+	} else {
+		fmt.Fprintf(&sb, "\n变更: 新增 %d 条，删除 %d 条，修改 %d 条\n", diff.Added, diff.Removed, diff.Changed)
 	}
+	return sb.String()
+}
 
-	// Find min and max years
-	minYear := years[0]
-	maxYear := years[0]
-	for _, year := range years {
-		if year < minYear {
-			minYear = year
-		}
-		if year > maxYear {
-			maxYear = year
-		}
+// runHeadless drives m to completion without a terminal, feeding it the
+// same messages the Bubble Tea program would.
+func runHeadless(m downloadModel) downloadModel {
+	m.startDownload()
+	for !m.done {
+		next, _ := m.Update(m.listenProgress())
+		m = next.(downloadModel)
 	}
-
-	return &YearInfo{
-		MinYear: minYear,
-		MaxYear: maxYear,
-		Count:   len(years),
-	}, nil
+	return m
 }
 
-// DownloadHolidays downloads the holidays JSON file and saves it to the cache directory.
-func DownloadHolidays() error {
+func runDownloader(dryRun bool, mirrors []string) error {
 	cachePath, err := GetCachePath()
 	if err != nil {
 		return err
 	}
+	if len(mirrors) == 0 {
+		mirrors = DefaultMirrors
+	}
 
-	m := newDownloadModel(holidaysURL, cachePath)
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		return err
+	model := newDownloadModel(mirrors, cachePath)
+	model.dryRun = dryRun
+	model.interactive = isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+	var m downloadModel
+	if model.interactive {
+		final, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if m, ok = final.(downloadModel); !ok || !m.done {
+			return nil
+		}
+	} else {
+		// Scripts and cron jobs have nothing to draw a progress bar on or
+		// read a key from, so skip Bubble Tea and just wait for the result.
+		m = runHeadless(model)
 	}
 
+	// The alternate screen is gone by now, so repeat the outcome where
+	// scripts and scrollback can see it.
 	if m.err != nil {
+		fmt.Fprint(os.Stderr, m.manualDownloadHelp())
 		return m.err
 	}
-
+	fmt.Print(m.resultMessage())
 	return nil
 }
@@ -0,0 +1,212 @@
+package holidays
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const sampleHolidayJSON = `[{"year": "2025", "holiday": {"10-01": {"holiday": true, "name": "国庆节", "wage": 3, "date": "2025-10-01"}}}]`
+
+func runDownload(t *testing.T, urls []string) downloadCompleteMsg {
+	t.Helper()
+	return runModel(t, newDownloadModel(urls, filepath.Join(t.TempDir(), "lucal", "holidays.json")))
+}
+
+func runModel(t *testing.T, m downloadModel) downloadCompleteMsg {
+	t.Helper()
+	m.startDownload()
+	select {
+	case msg := <-m.completeCh:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatalf("download did not complete")
+		return downloadCompleteMsg{}
+	}
+}
+
+func TestDownloadFallsBackToNextMirror(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleHolidayJSON))
+	}))
+	defer good.Close()
+
+	msg := runDownload(t, []string{broken.URL, good.URL})
+	if msg.err != nil {
+		t.Fatalf("expected success, got %v", msg.err)
+	}
+	if msg.source != good.URL {
+		t.Fatalf("source=%q want %q", msg.source, good.URL)
+	}
+	if msg.yearInfo == nil || msg.yearInfo.MaxYear != 2025 {
+		t.Fatalf("unexpected year info: %+v", msg.yearInfo)
+	}
+}
+
+func TestDownloadReportsAllMirrorFailures(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "missing", http.StatusNotFound)
+	}))
+	defer broken.Close()
+
+	urls := []string{broken.URL + "/a", broken.URL + "/b"}
+	msg := runDownload(t, urls)
+	if msg.err == nil {
+		t.Fatalf("expected failure when every mirror fails")
+	}
+	for _, url := range urls {
+		if !strings.Contains(msg.err.Error(), url) {
+			t.Fatalf("error should mention %s, got %v", url, msg.err)
+		}
+	}
+}
+
+func TestDownloadRejectsOversizedResponse(t *testing.T) {
+	SetMaxFileSize(int64(len(sampleHolidayJSON) - 1))
+	defer SetMaxFileSize(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleHolidayJSON))
+	}))
+	defer server.Close()
+
+	msg := runDownload(t, []string{server.URL})
+	if msg.err == nil || !strings.Contains(msg.err.Error(), ErrFileTooLarge.Error()) {
+		t.Fatalf("expected oversized download to fail, got %v", msg.err)
+	}
+}
+
+func TestDownloadSkipsMirrorWithInvalidJSON(t *testing.T) {
+	garbage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>captive portal</html>"))
+	}))
+	defer garbage.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleHolidayJSON))
+	}))
+	defer good.Close()
+
+	msg := runDownload(t, []string{garbage.URL, good.URL})
+	if msg.err != nil || msg.source != good.URL {
+		t.Fatalf("expected fallback past invalid JSON, got source=%q err=%v", msg.source, msg.err)
+	}
+}
+
+func TestDownloadRejectsHTMLPage(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"html content type": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(sampleHolidayJSON))
+		},
+		"html body": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("\n  <!DOCTYPE html><html><body>请先登录</body></html>"))
+		},
+		"empty body": func(w http.ResponseWriter, r *http.Request) {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			destPath := filepath.Join(t.TempDir(), "holidays.json")
+			msg := runModel(t, newDownloadModel([]string{server.URL}, destPath))
+			if msg.err == nil || !strings.Contains(msg.err.Error(), ErrNotJSON.Error()) {
+				t.Fatalf("expected ErrNotJSON, got %v", msg.err)
+			}
+			if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+				t.Fatalf("the cache should not be written, stat err=%v", err)
+			}
+		})
+	}
+}
+
+func TestDryRunLeavesCacheUntouched(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"year": "2025", "holiday": {
+			"10-01": {"holiday": true, "name": "国庆节", "wage": 3, "date": "2025-10-01"},
+			"10-02": {"holiday": true, "name": "国庆节", "wage": 3, "date": "2025-10-02"}
+		}}]`))
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "holidays.json")
+	cached := `[{"year": "2025", "holiday": {"10-01": {"holiday": true, "name": "国庆", "wage": 3, "date": "2025-10-01"}}}]`
+	if err := os.WriteFile(cachePath, []byte(cached), 0o644); err != nil {
+		t.Fatalf("failed to write cache: %v", err)
+	}
+
+	m := newDownloadModel([]string{server.URL}, cachePath)
+	m.dryRun = true
+	msg := runModel(t, m)
+	if msg.err != nil || !msg.dryRun || msg.diff == nil {
+		t.Fatalf("expected a dry-run result, got %+v", msg)
+	}
+	if msg.diff.Added != 1 || msg.diff.Changed != 1 || msg.diff.Removed != 0 {
+		t.Fatalf("unexpected diff %+v", *msg.diff)
+	}
+	if got, _ := os.ReadFile(cachePath); string(got) != cached {
+		t.Fatalf("dry run modified the cache: %s", got)
+	}
+}
+
+func TestResultScreenAutoExits(t *testing.T) {
+	m := newDownloadModel(nil, filepath.Join(t.TempDir(), "holidays.json"))
+	m.interactive = true
+	next, cmd := m.Update(downloadCompleteMsg{source: "https://example.com"})
+	if cmd == nil {
+		t.Fatalf("expected an auto-exit timer after completion")
+	}
+	if !strings.Contains(next.View(), "自动退出") {
+		t.Fatalf("result screen should mention the auto exit, got:\n%s", next.View())
+	}
+	if _, cmd := next.Update(autoExitMsg{}); cmd == nil {
+		t.Fatalf("expected auto-exit message to quit")
+	} else if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("expected tea.Quit, got %T", cmd())
+	}
+
+	m.interactive = false
+	_, cmd = m.Update(downloadCompleteMsg{source: "https://example.com"})
+	if cmd == nil {
+		t.Fatalf("expected non-interactive run to quit on completion")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("expected tea.Quit without a TTY, got %T", cmd())
+	}
+}
+
+func TestRunHeadless(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleHolidayJSON))
+	}))
+	defer server.Close()
+	destPath := filepath.Join(t.TempDir(), "lucal", "holidays.json")
+
+	m := runHeadless(newDownloadModel([]string{server.URL}, destPath))
+	if !m.done || m.err != nil {
+		t.Fatalf("expected a finished download, got done=%v err=%v", m.done, m.err)
+	}
+	if m.waitingKey {
+		t.Fatal("a headless download must not wait for a key press")
+	}
+	if !strings.Contains(m.resultMessage(), destPath) {
+		t.Fatalf("expected the result to mention %s, got:\n%s", destPath, m.resultMessage())
+	}
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+	failed := runHeadless(newDownloadModel([]string{broken.URL}, destPath))
+	if !failed.done || failed.err == nil {
+		t.Fatalf("expected every mirror to fail, got done=%v err=%v", failed.done, failed.err)
+	}
+}
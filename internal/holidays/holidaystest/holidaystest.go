@@ -0,0 +1,73 @@
+// Package holidaystest provides a small, deterministic holiday dataset for
+// tests, so they never depend on the downloaded cache.
+package holidaystest
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+// DefaultYear is the year Data covers when called without arguments.
+const DefaultYear = 2025
+
+// fixtureEntry is one day of the fixture, repeated in every requested year.
+type fixtureEntry struct {
+	key     string // MM-DD
+	holiday bool
+	name    string
+	wage    int
+	target  string
+	after   *bool // copied into every entry; nil leaves After unset
+}
+
+var (
+	before = false
+	after  = true
+)
+
+// fixture holds a single-day holiday (元旦), a seven-day block (国庆节) and
+// the 调休 workdays on either side of the block.
+var fixture = []fixtureEntry{
+	{key: "01-01", holiday: true, name: "元旦", wage: 3},
+	{key: "09-28", name: "国庆节前补班", wage: 1, target: "国庆节", after: &before},
+	{key: "10-01", holiday: true, name: "国庆节", wage: 3},
+	{key: "10-02", holiday: true, name: "国庆节", wage: 3},
+	{key: "10-03", holiday: true, name: "国庆节", wage: 3},
+	{key: "10-04", holiday: true, name: "国庆节", wage: 2},
+	{key: "10-05", holiday: true, name: "国庆节", wage: 2},
+	{key: "10-06", holiday: true, name: "国庆节", wage: 2},
+	{key: "10-07", holiday: true, name: "国庆节", wage: 2},
+	{key: "10-11", name: "国庆节后补班", wage: 1, target: "国庆节", after: &after},
+}
+
+// Data returns the fixture for each of years (DefaultYear when none are
+// given) in the year → MM-DD layout used by holidays.GetHolidayForDate.
+// Every call builds fresh entries, so callers may modify the result.
+func Data(years ...int) map[string]map[string]*holidays.HolidayEntry {
+	if len(years) == 0 {
+		years = []int{DefaultYear}
+	}
+	data := make(map[string]map[string]*holidays.HolidayEntry, len(years))
+	for _, year := range years {
+		yearStr := strconv.Itoa(year)
+		entries := make(map[string]*holidays.HolidayEntry, len(fixture))
+		for _, f := range fixture {
+			entry := &holidays.HolidayEntry{
+				Holiday: f.holiday,
+				Name:    f.name,
+				Wage:    f.wage,
+				Date:    fmt.Sprintf("%d-%s", year, f.key),
+				Target:  f.target,
+			}
+			if f.after != nil {
+				after := *f.after
+				entry.After = &after
+			}
+			entries[f.key] = entry
+		}
+		data[yearStr] = entries
+	}
+	return data
+}
@@ -0,0 +1,48 @@
+package holidaystest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+func TestDataShape(t *testing.T) {
+	data := Data(2024, 2025)
+	if len(data) != 2 || len(data["2024"]) != len(fixture) || len(data["2025"]) != len(fixture) {
+		t.Fatalf("expected %d entries in each of 2024 and 2025, got %v", len(fixture), data)
+	}
+	for year, entries := range data {
+		for key, entry := range entries {
+			if entry.Date != year+"-"+key {
+				t.Fatalf("entry %s/%s has date %q", year, key, entry.Date)
+			}
+		}
+	}
+
+	if info := holidays.GetHolidayForDate(data, 2025, 1, 1); info == nil || !info.IsHoliday || info.Name != "元旦" {
+		t.Fatalf("expected 元旦 on 2025-01-01, got %+v", info)
+	}
+	if info := holidays.GetHolidayForDate(data, 2024, 9, 28); info == nil || info.IsHoliday || info.Adjustment() != "为国庆节调休，节前上班" {
+		t.Fatalf("expected a 调休 workday on 2024-09-28, got %+v", info)
+	}
+	block := holidays.GetHolidaysInRange(data, time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 10, 7, 0, 0, 0, 0, time.Local))
+	if len(block) != 7 {
+		t.Fatalf("expected a seven-day 国庆节 block, got %d days", len(block))
+	}
+}
+
+func TestDataDefaultsAndIsolation(t *testing.T) {
+	data := Data()
+	if _, ok := data["2025"]; !ok || len(data) != 1 {
+		t.Fatalf("expected only %d by default, got %v", DefaultYear, data)
+	}
+	data["2025"]["01-01"].Name = "changed"
+	if Data()["2025"]["01-01"].Name != "元旦" {
+		t.Fatal("Data should build fresh entries on every call")
+	}
+	*data["2025"]["10-11"].After = false
+	if fresh := Data()["2025"]; !*fresh["10-11"].After || *fresh["09-28"].After {
+		t.Fatal("Data should not share After between calls")
+	}
+}
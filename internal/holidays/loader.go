@@ -2,31 +2,248 @@ package holidays
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
-// LoadFromFile loads holiday data from a JSON file.
-func LoadFromFile(path string) (map[string]map[string]*HolidayEntry, error) {
-	data, err := os.ReadFile(path)
+// DefaultMaxFileSize is the largest holiday file accepted unless changed with
+// SetMaxFileSize. Real datasets are a few hundred KB.
+const DefaultMaxFileSize int64 = 16 << 20
+
+var maxFileSize = DefaultMaxFileSize
+
+// SetMaxFileSize changes the largest holiday file the loader and downloader
+// accept. n <= 0 restores DefaultMaxFileSize.
+func SetMaxFileSize(n int64) {
+	if n <= 0 {
+		n = DefaultMaxFileSize
+	}
+	maxFileSize = n
+}
+
+// ErrFileTooLarge is returned for holiday files above the size cap.
+var ErrFileTooLarge = errors.New("节假日数据文件过大")
+
+// HolidayParseError reports holiday data that could not be decoded. Offset
+// is the byte offset of the problem when the JSON decoder reports one, and 0
+// otherwise. It wraps the decoder error, so errors.Is(err, ErrObjectShape)
+// still holds for object files of the wrong shape.
+type HolidayParseError struct {
+	Path   string // file path or URL
+	Offset int64
+	Err    error
+}
+
+func (e *HolidayParseError) Error() string {
+	if e.Offset > 0 {
+		return fmt.Sprintf("failed to parse holidays JSON %s at offset %d: %v", e.Path, e.Offset, e.Err)
+	}
+	return fmt.Sprintf("failed to parse holidays JSON %s: %v", e.Path, e.Err)
+}
+
+func (e *HolidayParseError) Unwrap() error { return e.Err }
+
+// decodeFile streams the holiday JSON at path, refusing files larger than the
+// cap before reading them. It returns the file size for logging.
+func decodeFile(path string) (HolidayData, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+	if info.Size() > maxFileSize {
+		return nil, 0, fmt.Errorf("%w: %s 为 %d 字节，上限为 %d 字节", ErrFileTooLarge, path, info.Size(), maxFileSize)
+	}
+
+	// The limit also covers files that grow after Stat or report no size.
+	holidayData, err := decodeCapped(file, path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read holidays file: %w", err)
+		return nil, 0, err
 	}
+	return holidayData, info.Size(), nil
+}
 
+// decodeCapped streams holiday JSON from r, failing with ErrFileTooLarge once
+// more than the cap has been read. source names r in parse errors.
+func decodeCapped(r io.Reader, source string) (HolidayData, error) {
+	limited := &io.LimitedReader{R: r, N: maxFileSize + 1}
 	var holidayData HolidayData
-	if err := json.Unmarshal(data, &holidayData); err != nil {
-		return nil, fmt.Errorf("failed to parse holidays JSON: %w", err)
+	err := json.NewDecoder(limited).Decode(&holidayData)
+	if limited.N <= 0 {
+		return nil, fmt.Errorf("%w: 超过 %d 字节上限", ErrFileTooLarge, maxFileSize)
+	}
+	if err != nil {
+		parseErr := &HolidayParseError{Path: source, Err: err}
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			parseErr.Offset = syntaxErr.Offset
+		case errors.As(err, &typeErr):
+			parseErr.Offset = typeErr.Offset
+		}
+		return nil, parseErr
+	}
+	return holidayData, nil
+}
+
+// IsURL reports whether source names an http(s) URL rather than a file.
+func IsURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// LoadFromURL fetches holiday data into memory with the downloader's HTTP
+// client, bypassing the cache, and normalizes it like Load.
+func LoadFromURL(url string) (map[string]map[string]*HolidayEntry, []Warning, error) {
+	holidayData, err := decodeURL(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, warnings := Normalize(holidayData)
+	slog.Debug("loaded holidays", "url", url, "years", len(result), "warnings", len(warnings))
+	return result, warnings, nil
+}
+
+// decodeURL fetches and decodes the holiday JSON at url, rejecting HTML
+// responses.
+func decodeURL(url string) (HolidayData, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holidays: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch holidays: HTTP %s", resp.Status)
+	}
+
+	body, err := sniffJSON(resp)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCapped(body, url)
+}
+
+// Warning describes a recoverable problem found while loading holiday
+// data. The affected entry is still used where possible. Warnings about the
+// data as a whole leave Year and Key empty.
+type Warning struct {
+	Year    string
+	Key     string
+	Message string
+}
+
+// emptyWarning reports holiday data without a single entry, such as "[]".
+var emptyWarning = Warning{Message: "节假日文件为空"}
+
+func (w Warning) String() string {
+	if w.Year == "" {
+		return w.Message
+	}
+	if w.Key == "" {
+		return fmt.Sprintf("%s: %s", w.Year, w.Message)
+	}
+	return fmt.Sprintf("%s/%s: %s", w.Year, w.Key, w.Message)
+}
+
+// LoadFromFile loads holiday data from a JSON file, discarding warnings.
+func LoadFromFile(path string) (map[string]map[string]*HolidayEntry, error) {
+	data, _, err := Load(path)
+	return data, err
+}
+
+// Load loads holiday data from a JSON file and normalizes it with Normalize,
+// returning the warnings that produced.
+func Load(path string) (map[string]map[string]*HolidayEntry, []Warning, error) {
+	holidayData, size, err := decodeFile(path)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Convert array format to map format for easier lookup
+	result, warnings := Normalize(holidayData)
+	slog.Debug("loaded holidays", "path", path, "bytes", size, "years", len(result), "warnings", len(warnings))
+
+	return result, warnings, nil
+}
+
+// entryDateLayout is the layout of HolidayEntry.Date.
+const entryDateLayout = "2006-01-02"
+
+// relocation is an entry waiting to be filed under the key its Date names.
+type relocation struct {
+	year, key string
+	entry     *HolidayEntry
+}
+
+// Normalize converts the array layout into the year → MM-DD lookup used by
+// GetHolidayForDate. An entry's own Date is authoritative: when it parses but
+// disagrees with the year or MM-DD key it was filed under, the entry is moved
+// to the key its Date names and a warning is recorded. Entries without a
+// usable Date stay under their key. Years without entries are dropped, so
+// data with no entries at all, such as "[]", normalizes to an empty map
+// (which Service.HasHolidayData reports as no data) plus a "节假日文件为空"
+// warning.
+func Normalize(holidayData HolidayData) (map[string]map[string]*HolidayEntry, []Warning) {
 	result := make(map[string]map[string]*HolidayEntry)
+	var warnings []Warning
+	put := func(year, key string, entry *HolidayEntry) {
+		if result[year] == nil {
+			result[year] = make(map[string]*HolidayEntry)
+		}
+		result[year][key] = entry
+	}
+	var moved []relocation
 	for _, yearData := range holidayData {
-		result[yearData.Year] = yearData.Holiday
+		for key, entry := range yearData.Holiday {
+			if entry == nil || entry.Date == "" {
+				put(yearData.Year, key, entry)
+				continue
+			}
+			date, err := time.Parse(entryDateLayout, entry.Date)
+			if err != nil {
+				warnings = append(warnings, Warning{yearData.Year, key, fmt.Sprintf("无法解析日期 %q，按键值使用", entry.Date)})
+				put(yearData.Year, key, entry)
+				continue
+			}
+			year, mmdd := date.Format("2006"), date.Format("01-02")
+			if year == yearData.Year && mmdd == key {
+				put(year, key, entry)
+				continue
+			}
+			warnings = append(warnings, Warning{yearData.Year, key, fmt.Sprintf("日期字段 %s 与键值不一致，以日期字段为准", entry.Date)})
+			moved = append(moved, relocation{year, mmdd, entry})
+		}
 	}
-
-	return result, nil
+	// Moved entries go in last so they never displace an entry whose key and
+	// Date already agree.
+	for _, m := range moved {
+		if existing, ok := result[m.year][m.key]; ok && existing != nil && existing.Date == m.entry.Date {
+			warnings = append(warnings, Warning{m.year, m.key, fmt.Sprintf("已存在日期为 %s 的条目，忽略重复条目 %q", m.entry.Date, m.entry.Name)})
+			continue
+		}
+		put(m.year, m.key, m.entry)
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Year != warnings[j].Year {
+			return warnings[i].Year < warnings[j].Year
+		}
+		return warnings[i].Key < warnings[j].Key
+	})
+	if len(result) == 0 {
+		warnings = append(warnings, emptyWarning)
+	}
+	return result, warnings
 }
 
 // GetCachePath returns the path to the holidays cache file in XDG cache directory.
@@ -47,7 +264,9 @@ func LoadFromCache() (map[string]map[string]*HolidayEntry, error) {
 	return LoadFromFile(cachePath)
 }
 
-// IsCacheValid checks if the cache file exists and is not older than 6 months.
+// IsCacheValid checks if the cache file exists, is not older than 6 months
+// and has a format version this build reads (see CacheVersion); unversioned
+// caches count as version 0 and are accepted.
 func IsCacheValid(cachePath string) (bool, error) {
 	info, err := os.Stat(cachePath)
 	if err != nil {
@@ -59,7 +278,18 @@ func IsCacheValid(cachePath string) (bool, error) {
 
 	// Check if file is older than 6 months (180 days)
 	sixMonthsAgo := time.Now().AddDate(0, -6, 0)
-	return info.ModTime().After(sixMonthsAgo), nil
+	if !info.ModTime().After(sixMonthsAgo) {
+		return false, nil
+	}
+	version, err := CacheVersion(cachePath)
+	if err != nil {
+		return false, err
+	}
+	if version < minCacheFormatVersion || version > CacheFormatVersion {
+		slog.Debug("holiday cache format unsupported", "path", cachePath, "version", version)
+		return false, nil
+	}
+	return true, nil
 }
 
 // GetHolidayForDate retrieves holiday information for a specific date.
@@ -81,9 +311,33 @@ func GetHolidayForDate(data map[string]map[string]*HolidayEntry, year int, month
 		return nil
 	}
 
-	return &HolidayInfo{
-		IsHoliday: entry.Holiday,
-		Name:      entry.Name,
-	}
+	info := newHolidayInfo(entry)
+	return &info
+}
+
+// IsWorkingDay reports whether t is a working day, combining the regular
+// Saturday/Sunday weekend with holiday overrides: a holiday entry turns a
+// weekday into a rest day and a 调休 entry turns a weekend into a working day.
+// reason explains the determination in a human readable form.
+func IsWorkingDay(data map[string]map[string]*HolidayEntry, t time.Time) (working bool, reason string) {
+	return WorkingDay(data, t, t.Weekday() == time.Saturday || t.Weekday() == time.Sunday)
 }
 
+// WorkingDay is IsWorkingDay for work weeks other than Monday-Friday: the
+// caller decides whether t falls on a weekend, and holiday overrides apply
+// on top as usual.
+func WorkingDay(data map[string]map[string]*HolidayEntry, t time.Time, weekend bool) (working bool, reason string) {
+	if info := GetHolidayForDate(data, t.Year(), int(t.Month()), t.Day()); info != nil {
+		if info.IsHoliday {
+			return false, "法定假日：" + info.Name
+		}
+		if weekend {
+			return true, "周末调休上班"
+		}
+		return true, "调休上班"
+	}
+	if weekend {
+		return false, "周末"
+	}
+	return true, "普通工作日"
+}
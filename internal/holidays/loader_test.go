@@ -0,0 +1,239 @@
+package holidays
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTempFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "holidays.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	return path
+}
+
+func TestLoadFromFileArrayLayout(t *testing.T) {
+	path := writeTempFile(t, `[
+		{"year": "2025", "holiday": {"10-01": {"holiday": true, "name": "国庆节", "wage": 3, "date": "2025-10-01"}}}
+	]`)
+	data, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	info := GetHolidayForDate(data, 2025, 10, 1)
+	if info == nil || !info.IsHoliday || info.Name != "国庆节" {
+		t.Fatalf("expected 国庆节 on 2025-10-01, got %+v", info)
+	}
+}
+
+func TestLoadEmptyFileWarns(t *testing.T) {
+	for _, content := range []string{`[]`, `[{"year": "2025", "holiday": {}}]`} {
+		data, warnings, err := Load(writeTempFile(t, content))
+		if err != nil {
+			t.Fatalf("Load(%s) returned error: %v", content, err)
+		}
+		if len(data) != 0 {
+			t.Fatalf("Load(%s) = %v, want no years", content, data)
+		}
+		if len(warnings) != 1 || warnings[0].String() != "节假日文件为空" {
+			t.Fatalf("Load(%s) warnings = %v, want the empty-file warning", content, warnings)
+		}
+	}
+}
+
+func TestLoadFromFileObjectLayout(t *testing.T) {
+	path := writeTempFile(t, `{
+		"2025": {"10-01": {"holiday": true, "name": "国庆节", "wage": 3, "date": "2025-10-01"}},
+		"2024": {"02-04": {"holiday": false, "name": "春节前补班", "wage": 1, "date": "2024-02-04"}}
+	}`)
+	data, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	if info := GetHolidayForDate(data, 2025, 10, 1); info == nil || !info.IsHoliday {
+		t.Fatalf("expected holiday on 2025-10-01, got %+v", info)
+	}
+	if info := GetHolidayForDate(data, 2024, 2, 4); info == nil || info.IsHoliday {
+		t.Fatalf("expected workday on 2024-02-04, got %+v", info)
+	}
+}
+
+func TestHolidayInfoAdjustment(t *testing.T) {
+	path := writeTempFile(t, `[
+		{"year": "2025", "holiday": {
+			"09-28": {"holiday": false, "name": "国庆节前补班", "wage": 1, "after": false, "target": "国庆节", "date": "2025-09-28"},
+			"10-11": {"holiday": false, "name": "国庆节后补班", "wage": 1, "after": true, "target": "国庆节", "date": "2025-10-11"},
+			"02-08": {"holiday": false, "name": "春节后补班", "wage": 1, "target": "春节", "rest": 7, "date": "2025-02-08"},
+			"01-26": {"holiday": false, "name": "春节前补班", "wage": 1, "date": "2025-01-26"},
+			"10-01": {"holiday": true, "name": "国庆节", "wage": 3, "target": "国庆节", "date": "2025-10-01"}
+		}}
+	]`)
+	data, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	tests := []struct {
+		month, day int
+		want       string
+	}{
+		{9, 28, "为国庆节调休，节前上班"},
+		{10, 11, "为国庆节调休，节后上班"},
+		{2, 8, "为春节调休，上班"},
+		{1, 26, ""},
+		{10, 1, ""},
+	}
+	for _, tt := range tests {
+		info := GetHolidayForDate(data, 2025, tt.month, tt.day)
+		if info == nil {
+			t.Fatalf("missing entry for %02d-%02d", tt.month, tt.day)
+		}
+		if got := info.Adjustment(); got != tt.want {
+			t.Fatalf("Adjustment() for %02d-%02d = %q want %q", tt.month, tt.day, got, tt.want)
+		}
+	}
+	if info := GetHolidayForDate(data, 2025, 2, 8); info.Rest != 7 || info.After != nil {
+		t.Fatalf("expected rest 7 and no direction, got %+v", info)
+	}
+}
+
+func TestLoadParseErrorOffset(t *testing.T) {
+	path := writeTempFile(t, `[{"year": "2025", "holiday": {"10-01": {"holiday": true,, }}}]`)
+	_, err := LoadFromFile(path)
+	var parseErr *HolidayParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected HolidayParseError, got %v", err)
+	}
+	// The offset counts the bytes read up to and including the second comma.
+	if parseErr.Path != path || parseErr.Offset != 57 {
+		t.Fatalf("unexpected parse error %+v", parseErr)
+	}
+}
+
+func TestLoadFromFileUnknownObjectLayout(t *testing.T) {
+	path := writeTempFile(t, `{"code": 0, "holiday": {"10-01": {"holiday": true, "name": "国庆节"}}}`)
+	_, err := LoadFromFile(path)
+	if !errors.Is(err, ErrObjectShape) {
+		t.Fatalf("expected ErrObjectShape, got %v", err)
+	}
+}
+
+func TestIsWorkingDay(t *testing.T) {
+	data := map[string]map[string]*HolidayEntry{
+		"2025": {
+			"10-01": {Holiday: true, Name: "国庆节", Date: "2025-10-01"},
+			"10-11": {Holiday: false, Name: "国庆节后补班", Date: "2025-10-11"},
+		},
+	}
+	tests := []struct {
+		name        string
+		date        time.Time
+		wantWorking bool
+		wantReason  string
+	}{
+		{"statutory holiday", time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local), false, "法定假日：国庆节"},
+		{"weekend workday", time.Date(2025, 10, 11, 0, 0, 0, 0, time.Local), true, "周末调休上班"},
+		{"regular weekday", time.Date(2025, 10, 15, 0, 0, 0, 0, time.Local), true, "普通工作日"},
+		{"regular weekend", time.Date(2025, 10, 19, 0, 0, 0, 0, time.Local), false, "周末"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			working, reason := IsWorkingDay(data, tt.date)
+			if working != tt.wantWorking || reason != tt.wantReason {
+				t.Fatalf("IsWorkingDay(%s)=(%v, %q) want (%v, %q)",
+					tt.date.Format("2006-01-02"), working, reason, tt.wantWorking, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestLoadReconcilesMismatchedDate(t *testing.T) {
+	path := writeTempFile(t, `[
+		{"year": "2025", "holiday": {
+			"10-01": {"holiday": true, "name": "国庆节", "wage": 3, "date": "2025-10-02"},
+			"10-03": {"holiday": true, "name": "国庆节", "wage": 3, "date": "2025-10-03"},
+			"10-04": {"holiday": true, "name": "国庆节", "wage": 2, "date": "not-a-date"}
+		}}
+	]`)
+	data, warnings, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if info := GetHolidayForDate(data, 2025, 10, 1); info != nil {
+		t.Fatalf("expected mismatched entry moved off 10-01, got %+v", info)
+	}
+	if info := GetHolidayForDate(data, 2025, 10, 2); info == nil || info.Name != "国庆节" {
+		t.Fatalf("expected entry filed under its date 10-02, got %+v", info)
+	}
+	if info := GetHolidayForDate(data, 2025, 10, 4); info == nil {
+		t.Fatalf("expected unparseable date to keep its key")
+	}
+	if len(warnings) != 2 || warnings[0].Key != "10-01" || warnings[1].Key != "10-04" {
+		t.Fatalf("expected warnings for 10-01 and 10-04, got %v", warnings)
+	}
+}
+
+func TestLoadRejectsOversizedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "huge.json")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create fixture: %v", err)
+	}
+	// A sparse file: large on Stat without occupying disk or memory.
+	if err := file.Truncate(DefaultMaxFileSize + 1); err != nil {
+		t.Fatalf("failed to size fixture: %v", err)
+	}
+	file.Close()
+
+	if _, _, err := Load(path); !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("expected ErrFileTooLarge, got %v", err)
+	}
+}
+
+func TestSetMaxFileSize(t *testing.T) {
+	SetMaxFileSize(8)
+	defer SetMaxFileSize(0)
+	path := writeTempFile(t, `[{"year": "2025", "holiday": {}}]`)
+	if _, err := LoadFromFile(path); !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("expected ErrFileTooLarge under an 8-byte cap, got %v", err)
+	}
+}
+
+func TestLoadFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/holidays.json":
+		case "/portal.json":
+			w.Write([]byte("<html>captive portal</html>"))
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"2025": {"10-01": {"holiday": true, "name": "国庆节", "date": "2025-10-01"}}}`))
+	}))
+	defer server.Close()
+
+	if !IsURL(server.URL) || IsURL("holidays.json") {
+		t.Fatalf("IsURL misclassified sources")
+	}
+	data, _, err := LoadFromURL(server.URL + "/holidays.json")
+	if err != nil {
+		t.Fatalf("LoadFromURL returned error: %v", err)
+	}
+	if info := GetHolidayForDate(data, 2025, 10, 1); info == nil || info.Name != "国庆节" {
+		t.Fatalf("expected 国庆节 on 2025-10-01, got %+v", info)
+	}
+	if _, _, err := LoadFromURL(server.URL + "/missing.json"); err == nil {
+		t.Fatalf("expected error for a 404")
+	}
+	if _, _, err := LoadFromURL(server.URL + "/portal.json"); !errors.Is(err, ErrNotJSON) {
+		t.Fatalf("expected ErrNotJSON for an HTML page, got %v", err)
+	}
+}
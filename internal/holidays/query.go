@@ -0,0 +1,64 @@
+package holidays
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DatedHoliday is a holiday entry resolved to its calendar date.
+type DatedHoliday struct {
+	Date time.Time
+	HolidayInfo
+}
+
+// GetHolidaysInRange returns every holiday and 调休 entry between since and
+// until (both inclusive, compared by calendar date) in chronological order.
+func GetHolidaysInRange(data map[string]map[string]*HolidayEntry, since, until time.Time) []DatedHoliday {
+	if data == nil {
+		return nil
+	}
+	since = truncateDay(since)
+	until = truncateDay(until)
+
+	var result []DatedHoliday
+	for year := since.Year(); year <= until.Year(); year++ {
+		for key, entry := range data[strconv.Itoa(year)] {
+			date, err := time.ParseInLocation("2006-01-02", strconv.Itoa(year)+"-"+key, since.Location())
+			if err != nil || date.Before(since) || date.After(until) {
+				continue
+			}
+			result = append(result, DatedHoliday{
+				Date:        date,
+				HolidayInfo: newHolidayInfo(entry),
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result
+}
+
+// Coverage reports the range of years present in data, or nil when it holds
+// no parseable year.
+func Coverage(data map[string]map[string]*HolidayEntry) *YearInfo {
+	var info *YearInfo
+	for yearStr := range data {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			continue
+		}
+		if info == nil {
+			info = &YearInfo{MinYear: year, MaxYear: year}
+		}
+		info.MinYear = min(info.MinYear, year)
+		info.MaxYear = max(info.MaxYear, year)
+		info.Count++
+	}
+	return info
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,47 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetHolidaysInRange(t *testing.T) {
+	data := map[string]map[string]*HolidayEntry{
+		"2024": {
+			"12-31": {Holiday: true, Name: "跨年"},
+		},
+		"2025": {
+			"10-02": {Holiday: true, Name: "国庆节"},
+			"10-01": {Holiday: true, Name: "国庆节"},
+			"09-28": {Holiday: false, Name: "国庆节前补班"},
+			"12-31": {Holiday: true, Name: "范围外"},
+		},
+	}
+	since := time.Date(2024, 12, 31, 0, 0, 0, 0, time.Local)
+	until := time.Date(2025, 10, 2, 0, 0, 0, 0, time.Local)
+
+	got := GetHolidaysInRange(data, since, until)
+	want := []string{"2024-12-31", "2025-09-28", "2025-10-01", "2025-10-02"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i, h := range got {
+		if d := h.Date.Format("2006-01-02"); d != want[i] {
+			t.Fatalf("entry %d date=%s want %s", i, d, want[i])
+		}
+	}
+	if got[1].IsHoliday {
+		t.Fatalf("expected 2025-09-28 to be a workday")
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	data := map[string]map[string]*HolidayEntry{"2013": {}, "2025": {}, "bad": {}}
+	info := Coverage(data)
+	if info == nil || info.MinYear != 2013 || info.MaxYear != 2025 || info.Count != 2 {
+		t.Fatalf("unexpected coverage: %+v", info)
+	}
+	if Coverage(nil) != nil {
+		t.Fatalf("expected nil coverage for nil data")
+	}
+}
@@ -1,7 +1,12 @@
 package holidays
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
 )
 
 // HolidayEntry represents a single holiday entry in the JSON data.
@@ -47,16 +52,106 @@ func (h *HolidayEntry) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// HolidayData represents the structure of the holidays JSON file.
-// It's a map from year string to a map of date strings (MM-DD) to HolidayEntry.
-type HolidayData []struct {
-	Year    string                           `json:"year"`
+// YearHolidays holds the holiday entries of a single year, keyed by MM-DD.
+type YearHolidays struct {
+	Year    string                   `json:"year"`
 	Holiday map[string]*HolidayEntry `json:"holiday"`
 }
 
+// HolidayData represents the structure of the holidays JSON file.
+// It's a list of years, each carrying a map of date strings (MM-DD) to
+// HolidayEntry.
+type HolidayData []YearHolidays
+
+// ErrObjectShape is returned when a holiday file is a JSON object that is not
+// keyed by year, so it can't be adapted to the expected array layout.
+var ErrObjectShape = errors.New("检测到对象格式，期望数组格式（[{\"year\": \"2025\", \"holiday\": {...}}] 或 {\"2025\": {...}}）")
+
+// UnmarshalJSON accepts both the canonical array layout and the object layout
+// used by many public datasets, where the top level maps a year to its
+// MM-DD entries ({"2025": {"01-01": {...}}}). Either may be wrapped in the
+// versioned envelope of the cache ({"version": 1, "holidays": [...]}).
+func (d *HolidayData) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		var years []YearHolidays
+		if err := json.Unmarshal(data, &years); err != nil {
+			return err
+		}
+		*d = years
+		return nil
+	}
+
+	var byKey map[string]json.RawMessage
+	if err := json.Unmarshal(data, &byKey); err == nil {
+		if _, ok := byKey["version"]; ok {
+			inner, err := unwrapEnvelope(data)
+			if err != nil {
+				return err
+			}
+			return d.UnmarshalJSON(inner)
+		}
+	}
+
+	var byYear map[string]map[string]*HolidayEntry
+	if err := json.Unmarshal(data, &byYear); err != nil {
+		return fmt.Errorf("%w: %v", ErrObjectShape, err)
+	}
+	years := make([]string, 0, len(byYear))
+	for year := range byYear {
+		if _, err := strconv.Atoi(year); err != nil {
+			return fmt.Errorf("%w: 键 %q 不是年份", ErrObjectShape, year)
+		}
+		years = append(years, year)
+	}
+	sort.Strings(years)
+
+	result := make(HolidayData, 0, len(years))
+	for _, year := range years {
+		result = append(result, YearHolidays{Year: year, Holiday: byYear[year]})
+	}
+	*d = result
+	return nil
+}
+
 // HolidayInfo contains information about a holiday for a specific date.
 type HolidayInfo struct {
 	IsHoliday bool   // true if it's a holiday, false if it's a workday (调休)
 	Name      string // Name of the holiday
+	Wage      int    // pay multiple the dataset reports, e.g. 3 for statutory days
+	// Optional 调休 details, only set when the dataset provides them.
+	Target string // holiday a workday makes up for, e.g. 国庆节
+	After  *bool  // true if the workday falls after Target, false if before
+	Rest   int    // rest days the dataset reports for the entry
 }
 
+func newHolidayInfo(entry *HolidayEntry) HolidayInfo {
+	info := HolidayInfo{
+		IsHoliday: entry.Holiday,
+		Name:      entry.Name,
+		Wage:      entry.Wage,
+		Target:    entry.Target,
+		After:     entry.After,
+	}
+	if entry.Rest != nil {
+		info.Rest = *entry.Rest
+	}
+	return info
+}
+
+// Adjustment describes which holiday a 调休 workday belongs to, such as
+// "为国庆节调休，节前上班". It returns "" for holidays and for workdays
+// without a Target.
+func (h HolidayInfo) Adjustment() string {
+	if h.IsHoliday || h.Target == "" {
+		return ""
+	}
+	switch {
+	case h.After == nil:
+		return "为" + h.Target + "调休，上班"
+	case *h.After:
+		return "为" + h.Target + "调休，节后上班"
+	default:
+		return "为" + h.Target + "调休，节前上班"
+	}
+}
@@ -0,0 +1,85 @@
+package holidays
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Report summarizes a holiday file checked by Validate.
+type Report struct {
+	Warnings []Warning
+	Years    int       // years with at least one entry
+	Coverage *YearInfo // nil when no year has entries
+	Holidays int       // days off
+	Workdays int       // 调休 working days
+}
+
+// Validate loads the holiday file or http(s) URL at source like Load and
+// LoadFromURL, without touching the cache, and reports on it. Besides the
+// warnings Normalize records, it flags years that are not numbers and
+// entries whose MM-DD key is not a real date and that carry no usable Date
+// to fall back on: GetHolidayForDate can never find those. Files that
+// cannot be read or decoded are returned as errors.
+func Validate(source string) (Report, error) {
+	var holidayData HolidayData
+	var err error
+	if IsURL(source) {
+		holidayData, err = decodeURL(source)
+	} else {
+		holidayData, _, err = decodeFile(source)
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	return check(holidayData), nil
+}
+
+func check(holidayData HolidayData) Report {
+	result, warnings := Normalize(holidayData)
+	warnings = append(warnings, keyWarnings(holidayData)...)
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Year != warnings[j].Year {
+			return warnings[i].Year < warnings[j].Year
+		}
+		return warnings[i].Key < warnings[j].Key
+	})
+
+	report := Report{Warnings: warnings, Years: len(result), Coverage: Coverage(result)}
+	for _, entries := range result {
+		for _, entry := range entries {
+			switch {
+			case entry == nil:
+			case entry.Holiday:
+				report.Holidays++
+			default:
+				report.Workdays++
+			}
+		}
+	}
+	return report
+}
+
+// keyWarnings reports malformed years and the keys of entries that would be
+// filed under a date that does not exist.
+func keyWarnings(holidayData HolidayData) []Warning {
+	var warnings []Warning
+	for _, yearData := range holidayData {
+		if _, err := strconv.Atoi(yearData.Year); err != nil {
+			warnings = append(warnings, Warning{yearData.Year, "", "年份不是数字，其中的条目不会生效"})
+			continue
+		}
+		for key, entry := range yearData.Holiday {
+			if entry != nil && entry.Date != "" {
+				if _, err := time.Parse(entryDateLayout, entry.Date); err == nil {
+					continue // Normalize files it under its Date.
+				}
+			}
+			if _, err := time.Parse(entryDateLayout, yearData.Year+"-"+key); err != nil {
+				warnings = append(warnings, Warning{yearData.Year, key, fmt.Sprintf("键值 %q 不是有效的 MM-DD 日期，该条目不会生效", key)})
+			}
+		}
+	}
+	return warnings
+}
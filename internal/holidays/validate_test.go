@@ -0,0 +1,47 @@
+package holidays
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	path := writeTempFile(t, `[
+		{"year": "2025", "holiday": {
+			"10-01": {"holiday": true, "name": "国庆节", "date": "2025-10-01"},
+			"10-02": {"holiday": true, "name": "国庆节", "date": "2025-10-03"},
+			"09-28": {"holiday": false, "name": "国庆节前补班", "date": "2025-09-28"},
+			"2-30": {"holiday": true, "name": "不存在"}
+		}},
+		{"year": "二〇二六", "holiday": {"01-01": {"holiday": true, "name": "元旦"}}}
+	]`)
+	report, err := Validate(path)
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if report.Years != 2 || report.Holidays != 4 || report.Workdays != 1 {
+		t.Fatalf("unexpected counts: %+v", report)
+	}
+	if report.Coverage == nil || report.Coverage.MinYear != 2025 || report.Coverage.MaxYear != 2025 {
+		t.Fatalf("unexpected coverage: %+v", report.Coverage)
+	}
+
+	var got []string
+	for _, w := range report.Warnings {
+		got = append(got, w.String())
+	}
+	want := []string{
+		"2025/10-02: 日期字段 2025-10-03 与键值不一致，以日期字段为准",
+		`2025/2-30: 键值 "2-30" 不是有效的 MM-DD 日期，该条目不会生效`,
+		"二〇二六: 年份不是数字，其中的条目不会生效",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("warnings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateUnreadable(t *testing.T) {
+	if _, err := Validate(writeTempFile(t, `[{"year": `)); err == nil {
+		t.Fatal("expected an error for truncated JSON")
+	}
+}
@@ -0,0 +1,47 @@
+package notes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DateLayout is the key format used in notes files.
+const DateLayout = "2006-01-02"
+
+// LoadFromFile loads personal notes from a JSON file that maps dates
+// (YYYY-MM-DD) to short labels:
+//
+//	{"2025-11-11": "生日", "2025-11-25": "项目评审"}
+func LoadFromFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes file: %w", err)
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse notes JSON: %w", err)
+	}
+
+	result := make(map[string]string, len(raw))
+	for key, label := range raw {
+		date, err := time.Parse(DateLayout, strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid note date %q, expected YYYY-MM-DD", key)
+		}
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+		result[Key(date)] = label
+	}
+	return result, nil
+}
+
+// Key returns the lookup key for the given date.
+func Key(t time.Time) string {
+	return t.Format(DateLayout)
+}
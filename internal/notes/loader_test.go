@@ -0,0 +1,36 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	content := `{"2025-11-11": " 生日 ", "2025-11-12": ""}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	data, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	if got := data[Key(time.Date(2025, 11, 11, 0, 0, 0, 0, time.Local))]; got != "生日" {
+		t.Fatalf("expected trimmed note, got %q", got)
+	}
+	if _, ok := data["2025-11-12"]; ok {
+		t.Fatalf("expected empty note to be dropped")
+	}
+}
+
+func TestLoadFromFileInvalidDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	if err := os.WriteFile(path, []byte(`{"11-11": "生日"}`), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	if _, err := LoadFromFile(path); err == nil {
+		t.Fatalf("expected error for a key without a year")
+	}
+}
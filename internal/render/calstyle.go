@@ -0,0 +1,95 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/textwidth"
+)
+
+// FormatCal mimics the classic Unix cal(1) layout.
+const FormatCal = "cal"
+
+const (
+	calMonthWidth = 20 // "Su Mo Tu We Th Fr Sa"
+	calWeekRows   = 6
+	calColumns    = 3
+	calGutter     = "  "
+)
+
+var calWeekdays = []string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
+
+// RenderCalStyle writes views the way cal(1) does: English headers,
+// right-aligned two-digit days, no lunar data, colors or borders. A year
+// request is laid out as four rows of three months under a centered year;
+// when its months cross into the next year, as a fiscal year does, the header
+// shows the span, e.g. "2025-2026", and every month title its year.
+func RenderCalStyle(w io.Writer, req calendar.Request, views []calendar.MonthView) error {
+	if req.Mode != calendar.ModeYear {
+		for _, view := range views {
+			title := fmt.Sprintf("%s %d", view.Month, view.Year)
+			for _, line := range calMonthLines(view, title) {
+				if _, err := fmt.Fprintln(w, line); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	header := fmt.Sprintf("%d", req.Year)
+	spansYears := len(views) > 0 && views[0].Year != views[len(views)-1].Year
+	if spansYears {
+		header = fmt.Sprintf("%d-%d", views[0].Year, views[len(views)-1].Year)
+	}
+	rowWidth := calMonthWidth*calColumns + len(calGutter)*(calColumns-1)
+	lines := []string{textwidth.Center(header, rowWidth), ""}
+	for start := 0; start < len(views); start += calColumns {
+		end := min(start+calColumns, len(views))
+		blocks := make([][]string, 0, calColumns)
+		for _, view := range views[start:end] {
+			title := view.Month.String()
+			if spansYears {
+				title = fmt.Sprintf("%s %d", view.Month, view.Year)
+			}
+			blocks = append(blocks, calMonthLines(view, title))
+		}
+		for i := range blocks[0] {
+			parts := make([]string, len(blocks))
+			for j, block := range blocks {
+				parts[j] = block[i]
+			}
+			lines = append(lines, strings.Join(parts, calGutter))
+		}
+		if end != len(views) {
+			lines = append(lines, "")
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// calMonthLines renders one month as fixed-width lines: title, weekday header
+// and always six week rows so months line up side by side.
+func calMonthLines(view calendar.MonthView, title string) []string {
+	lines := make([]string, 0, calWeekRows+2)
+	lines = append(lines, textwidth.Center(title, calMonthWidth))
+	lines = append(lines, strings.Join(rotateWeekdays(calWeekdays, view.WeekStart), " "))
+	for _, week := range view.Weeks {
+		cells := make([]string, len(week))
+		for i, day := range week {
+			if day.InMonth {
+				cells[i] = fmt.Sprintf("%2d", day.Date.Day())
+			} else {
+				cells[i] = "  "
+			}
+		}
+		lines = append(lines, strings.Join(cells, " "))
+	}
+	for len(lines) < calWeekRows+2 {
+		lines = append(lines, strings.Repeat(" ", calMonthWidth))
+	}
+	return lines
+}
@@ -0,0 +1,91 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+// HolidayRule gives the holidays it matches a color of their own, e.g.
+// statutory days (wage 3) in red. A rule needs MinWage, Name or both, and
+// then matches holidays meeting all of them.
+type HolidayRule struct {
+	MinWage int    `json:"min_wage,omitempty"` // lowest wage the holiday data reports
+	Name    string `json:"name,omitempty"`     // regular expression matched against the holiday name
+	Color   string `json:"color"`              // "#RRGGBB"
+	Label   string `json:"label,omitempty"`    // legend entry; "" keeps the rule out of the legend
+}
+
+// holidayCategory is a validated HolidayRule.
+type holidayCategory struct {
+	minWage int
+	name    *regexp.Regexp
+	color   string // palette sequence
+	label   string
+}
+
+// holidayCategories are tried in order by dayColor; holidays none of them
+// match keep the holiday color.
+var holidayCategories []holidayCategory
+
+// LoadHolidayRules reads a JSON array of HolidayRule such as
+//
+//	[{"min_wage": 3, "color": "#EF4444", "label": "法定节假日"}]
+func LoadHolidayRules(path string) ([]HolidayRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holiday rules: %w", err)
+	}
+	var rules []HolidayRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse holiday rules JSON: %w", err)
+	}
+	return rules, nil
+}
+
+// SetHolidayRules validates rules and makes holidays matching one of them,
+// in order, use its color instead of the holiday color. No rules restores
+// the single holiday color. Nothing changes when it fails.
+func SetHolidayRules(rules []HolidayRule) error {
+	categories := make([]holidayCategory, 0, len(rules))
+	for i, rule := range rules {
+		if rule.MinWage == 0 && rule.Name == "" {
+			return fmt.Errorf("holiday rule %d: needs min_wage or name", i+1)
+		}
+		color, err := foregroundSequence(rule.Color)
+		if err != nil {
+			return fmt.Errorf("holiday rule %d: %w", i+1, err)
+		}
+		category := holidayCategory{minWage: rule.MinWage, color: color, label: rule.Label}
+		if rule.Name != "" {
+			if category.name, err = regexp.Compile(rule.Name); err != nil {
+				return fmt.Errorf("holiday rule %d: invalid name pattern: %w", i+1, err)
+			}
+		}
+		categories = append(categories, category)
+	}
+	holidayCategories = categories
+	return nil
+}
+
+// matches reports whether info falls into c.
+func (c holidayCategory) matches(info *holidays.HolidayInfo) bool {
+	if info.Wage < c.minWage {
+		return false
+	}
+	return c.name == nil || c.name.MatchString(info.Name)
+}
+
+// holidayColor is the color and legend label of the holiday described by
+// info: those of the first matching category, or the holiday color.
+func holidayColor(info *holidays.HolidayInfo) (color, label string) {
+	for _, category := range holidayCategories {
+		if category.matches(info) {
+			return category.color, category.label
+		}
+	}
+	return colors.holiday, "节假日"
+}
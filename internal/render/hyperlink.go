@@ -0,0 +1,72 @@
+package render
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// hyperlinkTemplate is the URL template day numbers link to; "" disables
+// hyperlinks.
+var hyperlinkTemplate string
+
+// SetHyperlinks wraps every day number in an OSC 8 hyperlink to template
+// expanded by HyperlinkURL. An empty template turns hyperlinks off.
+func SetHyperlinks(template string) {
+	hyperlinkTemplate = template
+}
+
+// HyperlinkURL expands the placeholders {date} (YYYY-MM-DD), {year},
+// {month} and {day} (both zero-padded) in template.
+func HyperlinkURL(template string, date time.Time) string {
+	return strings.NewReplacer(
+		"{date}", date.Format("2006-01-02"),
+		"{year}", strconv.Itoa(date.Year()),
+		"{month}", date.Format("01"),
+		"{day}", date.Format("02"),
+	).Replace(template)
+}
+
+// hyperlink wraps text in an OSC 8 sequence pointing at url.
+func hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// HyperlinksSupported reports whether fd is a terminal that is known to
+// understand OSC 8. FORCE_HYPERLINK=1 (or 0) overrides the detection.
+func HyperlinksSupported(fd uintptr) bool {
+	if force := os.Getenv("FORCE_HYPERLINK"); force != "" {
+		return force != "0"
+	}
+	return isatty.IsTerminal(fd) && hyperlinkTerminal(os.Getenv)
+}
+
+// hyperlinkTerminal guesses OSC 8 support from the environment terminal
+// emulators are known to set. Unknown terminals are assumed not to support
+// it, since some print the sequences verbatim.
+func hyperlinkTerminal(getenv func(string) string) bool {
+	term := getenv("TERM")
+	if term == "dumb" {
+		return false
+	}
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "Tabby", "rio":
+		return true
+	}
+	if getenv("KITTY_WINDOW_ID") != "" || getenv("WT_SESSION") != "" || getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	// GNOME Terminal and other VTE terminals gained OSC 8 in VTE 0.50.
+	if version, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && version >= 5000 {
+		return true
+	}
+	for _, name := range []string{"kitty", "foot", "ghostty", "alacritty", "wezterm"} {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/holidays"
+)
+
+// Supported output formats for the non-interactive renderer.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// JSONOptions controls RenderJSON.
+type JSONOptions struct {
+	// Compact writes one single-line document per month (newline-delimited
+	// JSON) instead of one indented document, so output can be streamed.
+	Compact      bool
+	Request      calendar.Request
+	ToYear       int // last year of a year range; 0 for a single view
+	FiscalStart  int // month a year request's fiscal year begins in; 0 or 1 for calendar years
+	GeneratedAt  time.Time
+	HolidayYears *holidays.YearInfo // nil when no holiday data is loaded
+	DateFormat   string             // layout of day dates; "" means DefaultDateFormat
+	// ISOWeek adds each day's ISO 8601 week and week-based year, which
+	// differ from the calendar year around New Year.
+	ISOWeek bool
+}
+
+// DefaultDateFormat is the ISO 8601 layout used for day dates.
+const DefaultDateFormat = "2006-01-02"
+
+// dateFormatPresets names the common layouts accepted by ParseDateFormat.
+var dateFormatPresets = map[string]string{
+	"iso":     DefaultDateFormat,
+	"slash":   "2006/01/02",
+	"compact": "20060102",
+}
+
+// ParseDateFormat resolves a preset name (iso, slash, compact) or a Go
+// reference-time layout. A layout is accepted only if a formatted date
+// parses back to the same day, so it must carry the year, month and day.
+func ParseDateFormat(value string) (string, error) {
+	if layout, ok := dateFormatPresets[value]; ok {
+		return layout, nil
+	}
+	sample := time.Date(2025, time.November, 28, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(value, sample.Format(value))
+	if err != nil || !sameDate(parsed, sample) {
+		return "", fmt.Errorf("日期格式 %q 无法还原年月日，可用 iso、slash、compact 或 Go 时间布局（如 2006-01-02）", value)
+	}
+	return value, nil
+}
+
+func sameDate(a, b time.Time) bool {
+	y1, m1, d1 := a.Date()
+	y2, m2, d2 := b.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+type jsonOutput struct {
+	Meta   jsonMeta    `json:"meta"`
+	Months []jsonMonth `json:"months"`
+}
+
+type jsonMeta struct {
+	Request      jsonRequest    `json:"request"`
+	GeneratedAt  string         `json:"generated_at"`
+	WeekStart    int            `json:"week_start"` // time.Weekday, 0=Sunday
+	HolidayYears *jsonYearRange `json:"holiday_years,omitempty"`
+}
+
+type jsonRequest struct {
+	Year        int    `json:"year"`
+	Month       int    `json:"month,omitempty"`
+	ToYear      int    `json:"to_year,omitempty"`
+	Mode        string `json:"mode"`
+	FiscalStart int    `json:"fiscal_start,omitempty"` // first month of each fiscal year
+}
+
+type jsonYearRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+type jsonMonth struct {
+	Year  int       `json:"year"`
+	Month int       `json:"month"`
+	Title string    `json:"title"`
+	Days  []jsonDay `json:"days"`
+}
+
+type jsonDay struct {
+	Date          string       `json:"date"`
+	Weekday       int          `json:"weekday"`
+	DayOfYear     int          `json:"day_of_year"`
+	ISOWeek       int          `json:"iso_week,omitempty"`
+	ISOWeekYear   int          `json:"iso_week_year,omitempty"`
+	WesternZodiac string       `json:"western_zodiac"`
+	LunarMonth    string       `json:"lunar_month,omitempty"`
+	LunarDay      string       `json:"lunar_day,omitempty"`
+	LunarDate     string       `json:"lunar_date,omitempty"`
+	LeapMonth     bool         `json:"leap_month,omitempty"`
+	YearNayin     string       `json:"year_nayin,omitempty"`
+	DayNayin      string       `json:"day_nayin"`
+	SolarTerm     string       `json:"solar_term,omitempty"`
+	Pentad        string       `json:"pentad,omitempty"`
+	SolarTermTime string       `json:"solar_term_time,omitempty"` // RFC 3339
+	IsToday       bool         `json:"is_today"`
+	Holiday       *jsonHoliday `json:"holiday,omitempty"`
+	Note          string       `json:"note,omitempty"`
+	Marked        bool         `json:"marked,omitempty"`
+	Yi            []string     `json:"yi,omitempty"`
+	Ji            []string     `json:"ji,omitempty"`
+}
+
+type jsonHoliday struct {
+	Name       string `json:"name"`
+	IsHoliday  bool   `json:"is_holiday"`
+	Target     string `json:"target,omitempty"`
+	After      *bool  `json:"after,omitempty"`
+	Rest       int    `json:"rest,omitempty"`
+	Adjustment string `json:"adjustment,omitempty"`
+}
+
+// RenderJSON writes the in-month days of views, preceded by metadata
+// describing the request. By default this is a single indented document; in
+// compact mode every month becomes its own line carrying the same metadata.
+func RenderJSON(w io.Writer, views []calendar.MonthView, opts JSONOptions) error {
+	meta := newJSONMeta(views, opts)
+	layout := opts.DateFormat
+	if layout == "" {
+		layout = DefaultDateFormat
+	}
+	months := make([]jsonMonth, 0, len(views))
+	for _, view := range views {
+		month := jsonMonth{
+			Year:  view.Year,
+			Month: int(view.Month),
+			Title: view.Title,
+			Days:  make([]jsonDay, 0, 31),
+		}
+		for _, day := range view.Days() {
+			d := newJSONDay(day, layout)
+			if opts.ISOWeek {
+				d.ISOWeekYear, d.ISOWeek = day.Date.ISOWeek()
+			}
+			month.Days = append(month.Days, d)
+		}
+		months = append(months, month)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if !opts.Compact {
+		enc.SetIndent("", "  ")
+		return enc.Encode(jsonOutput{Meta: meta, Months: months})
+	}
+	for _, month := range months {
+		if err := enc.Encode(jsonOutput{Meta: meta, Months: []jsonMonth{month}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newJSONMeta(views []calendar.MonthView, opts JSONOptions) jsonMeta {
+	req := opts.Request.Normalize()
+	meta := jsonMeta{
+		Request:     jsonRequest{Year: req.Year, Month: req.Month, Mode: "month"},
+		GeneratedAt: opts.GeneratedAt.Format(time.RFC3339),
+	}
+	if req.Mode == calendar.ModeYear {
+		meta.Request.Mode = "year"
+		meta.Request.Month = 0
+		if opts.ToYear > req.Year {
+			meta.Request.ToYear = opts.ToYear
+		}
+		if opts.FiscalStart > 1 {
+			meta.Request.FiscalStart = opts.FiscalStart
+		}
+	}
+	if len(views) > 0 {
+		meta.WeekStart = int(views[0].WeekStart)
+	}
+	if opts.HolidayYears != nil {
+		meta.HolidayYears = &jsonYearRange{Min: opts.HolidayYears.MinYear, Max: opts.HolidayYears.MaxYear}
+	}
+	return meta
+}
+
+func newJSONDay(day calendar.Day, layout string) jsonDay {
+	d := jsonDay{
+		Date:          day.Date.Format(layout),
+		Weekday:       int(day.Date.Weekday()),
+		DayOfYear:     day.Date.YearDay(),
+		WesternZodiac: calendar.WesternZodiac(day.Date),
+		LunarMonth:    day.LunarMonthAlias,
+		LunarDay:      day.LunarDayAlias,
+		LunarDate:     day.LunarDateString(),
+		LeapMonth:     day.IsLeapMonth,
+		YearNayin:     day.YearNayin(),
+		DayNayin:      day.DayNayin(),
+		SolarTerm:     day.SolarTerm,
+		Pentad:        day.Pentad(),
+		IsToday:       day.IsToday,
+		Note:          day.Note,
+		Marked:        day.Marked,
+		Yi:            day.Yi,
+		Ji:            day.Ji,
+	}
+	if !day.SolarTermTime.IsZero() {
+		d.SolarTermTime = day.SolarTermTime.Format(time.RFC3339)
+	}
+	if day.HolidayInfo != nil {
+		d.Holiday = &jsonHoliday{
+			Name:       day.HolidayInfo.Name,
+			IsHoliday:  day.HolidayInfo.IsHoliday,
+			Target:     day.HolidayInfo.Target,
+			After:      day.HolidayInfo.After,
+			Rest:       day.HolidayInfo.Rest,
+			Adjustment: day.HolidayInfo.Adjustment(),
+		}
+	}
+	return d
+}
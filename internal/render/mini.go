@@ -0,0 +1,50 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/textwidth"
+)
+
+// FormatMini selects RenderMini for each requested month.
+const FormatMini = "mini"
+
+// MiniWidth is the exact width of every line RenderMini produces: seven
+// three-column cells plus one column for today's closing bracket.
+const MiniWidth = 22
+
+// RenderMini renders view as a tiny grid for shell prompts and status bars:
+// a "11月" title and one line per week with day numbers only. Today is
+// wrapped in brackets instead of colored so it survives any embedding. Every
+// line is padded to exactly MiniWidth columns.
+func RenderMini(view calendar.MonthView) string {
+	lines := make([]string, 0, len(view.Weeks)+1)
+	lines = append(lines, textwidth.Center(fmt.Sprintf("%d月", int(view.Month)), MiniWidth))
+	for _, week := range view.Weeks {
+		line := make([]byte, 0, MiniWidth)
+		closeToday := false
+		for _, day := range week {
+			lead := byte(' ')
+			switch {
+			case day.InMonth && day.IsToday:
+				lead = '['
+			case closeToday:
+				lead = ']'
+			}
+			closeToday = day.InMonth && day.IsToday
+			cell := "  "
+			if day.InMonth {
+				cell = fmt.Sprintf("%2d", day.Date.Day())
+			}
+			line = append(line, lead)
+			line = append(line, cell...)
+		}
+		if closeToday {
+			line = append(line, ']')
+		}
+		lines = append(lines, textwidth.PadRight(string(line), MiniWidth))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -3,12 +3,16 @@ package render
 import (
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/mattn/go-isatty"
 	"golang.org/x/term"
 
 	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/textwidth"
 )
 
 // PlainOptions controls how the non-interactive renderer behaves.
@@ -18,6 +22,24 @@ type PlainOptions struct {
 	Request           calendar.Request
 	Width             int
 	HolidayCacheValid bool
+	Format            string // FormatText (default), FormatJSON, FormatCal, FormatMini, FormatSVG or FormatPNG
+	CompactJSON       bool   // newline-delimited JSON, one month per line
+	// ToYear extends a ModeYear request to every year from Request.Year
+	// through ToYear. Ignored unless it is after Request.Year.
+	ToYear int
+	// DateFormat is the layout of day dates in JSON; "" means
+	// DefaultDateFormat. See ParseDateFormat.
+	DateFormat string
+	// FiscalStart is the month (2-12) a ModeYear request's year begins in:
+	// each year then spans twelve months from that month and is titled with
+	// its fiscal span. 0 or 1 keeps calendar years.
+	FiscalStart int
+	// ISOWeek adds the ISO 8601 week and week-based year of every day to
+	// JSON output.
+	ISOWeek bool
+	// Quiet prints the grid alone, without the summaries, color legend and
+	// update hint that normally follow it.
+	Quiet bool
 }
 
 // RunPlain renders the requested view exactly once.
@@ -30,56 +52,252 @@ func RunPlain(opts PlainOptions) error {
 	}
 
 	req := opts.Request.Normalize()
-	views, err := fetchViews(opts.Service, req)
-	if err != nil {
-		return err
+	years := []calendar.Request{req}
+	if req.Mode == calendar.ModeYear {
+		for year := req.Year + 1; year <= opts.ToYear; year++ {
+			years = append(years, calendar.Request{Year: year, Month: 1, Mode: calendar.ModeYear})
+		}
 	}
-	blocks, err := BuildBlocks(views)
-	if err != nil {
-		return err
+	switch opts.Format {
+	case FormatJSON:
+		views, err := fetchRange(opts.Service, years, opts.FiscalStart)
+		if err != nil {
+			return err
+		}
+		jsonOpts := JSONOptions{
+			Compact:      opts.CompactJSON,
+			Request:      req,
+			GeneratedAt:  opts.Service.Now(),
+			HolidayYears: opts.Service.HolidayCoverage(),
+			DateFormat:   opts.DateFormat,
+			ISOWeek:      opts.ISOWeek,
+			FiscalStart:  opts.FiscalStart,
+		}
+		if len(years) > 1 {
+			jsonOpts.ToYear = opts.ToYear
+		}
+		return RenderJSON(opts.Writer, views, jsonOpts)
+	case FormatCal:
+		for idx, yearReq := range years {
+			views, err := fetchViews(opts.Service, yearReq, opts.FiscalStart)
+			if err != nil {
+				return err
+			}
+			if idx > 0 {
+				if _, err := fmt.Fprintln(opts.Writer); err != nil {
+					return err
+				}
+			}
+			if err := RenderCalStyle(opts.Writer, yearReq, views); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatSVG:
+		views, err := fetchRange(opts.Service, years, opts.FiscalStart)
+		if err != nil {
+			return err
+		}
+		return RenderSVG(opts.Writer, views, SVGOptions{})
+	case FormatPNG:
+		views, err := fetchRange(opts.Service, years, opts.FiscalStart)
+		if err != nil {
+			return err
+		}
+		return RenderPNG(opts.Writer, views, PNGOptions{})
+	case FormatMini:
+		views, err := fetchRange(opts.Service, years, opts.FiscalStart)
+		if err != nil {
+			return err
+		}
+		for idx, view := range views {
+			if idx > 0 {
+				if _, err := fmt.Fprintln(opts.Writer); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintln(opts.Writer, RenderMini(view)); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 	width := opts.Width
 	if width == 0 {
 		width = DetectWidth()
 	}
-	output := Layout(blocks, width)
-	if output == "" {
+	// A year range is written one year at a time, so long ranges start
+	// printing right away.
+	var views []calendar.MonthView
+	for idx, yearReq := range years {
+		yearViews, err := fetchViews(opts.Service, yearReq, opts.FiscalStart)
+		if err != nil {
+			return err
+		}
+		views = append(views, yearViews...)
+		blocks, err := BuildBlocks(yearViews)
+		if err != nil {
+			return err
+		}
+		cols := LayoutColumns(blocks, width)
+		if idx == 0 && yearColumns > 0 && len(blocks) > 1 {
+			if need := GridWidth(blocks, cols); need > width {
+				fmt.Fprintf(os.Stderr, "警告: %d 列布局需要 %d 列宽度，超出终端宽度 %d\n", cols, need, width)
+			}
+		}
+		output := Layout(blocks, width)
+		if output == "" {
+			continue
+		}
+		if widthReport != nil {
+			reportWidths(widthReport, blocks, cols, width)
+		}
+		if fiscal := yearReq.Mode == calendar.ModeYear && opts.FiscalStart > 1; fiscal || len(years) > 1 {
+			header := fmt.Sprintf("%d 年", yearReq.Year)
+			if fiscal {
+				header = fiscalTitle(yearReq.Year, opts.FiscalStart)
+			}
+			output = yearHeader(header, GridWidth(blocks, cols)) + "\n\n" + output
+			if idx > 0 {
+				output = "\n" + output
+			}
+		}
+		if _, err := fmt.Fprintln(opts.Writer, output); err != nil {
+			return err
+		}
+	}
+
+	if opts.Quiet {
 		return nil
 	}
-	_, err = fmt.Fprintln(opts.Writer, output)
-	if err != nil {
-		return err
+	var err error
+	if summary := NotesSummary(views); summary != "" {
+		if _, err = fmt.Fprintln(opts.Writer, "\n"+summary); err != nil {
+			return err
+		}
+	}
+	if summary := MarksSummary(views); summary != "" {
+		if _, err = fmt.Fprintln(opts.Writer, "\n"+summary); err != nil {
+			return err
+		}
+	}
+	if summary := WorkdaySummary(views); summary != "" {
+		if _, err = fmt.Fprintln(opts.Writer, "\n"+summary); err != nil {
+			return err
+		}
+	}
+	if summary := AlmanacSummary(views); summary != "" {
+		if _, err = fmt.Fprintln(opts.Writer, "\n"+summary); err != nil {
+			return err
+		}
+	}
+	if summary := PentadSummary(views); summary != "" {
+		if _, err = fmt.Fprintln(opts.Writer, "\n"+summary); err != nil {
+			return err
+		}
 	}
 
-	// Show color legend if holiday data is available
-	if opts.Service != nil && opts.Service.HasHolidayData() {
-		legend := ColorLegend()
-		// Remove ANSI codes for plain output if needed, but keep the text
+	if legend := ColorLegend(views, width); legend != "" {
 		_, err = fmt.Fprintln(opts.Writer, "\n"+legend)
 		if err != nil {
 			return err
 		}
 	}
 
-	if !opts.HolidayCacheValid {
-		_, err = fmt.Fprintln(opts.Writer, "\n尚未下载节假日数据或节假日数据超过 6 个月未更新，运行  lucal -u 获取最新数据")
+	if hint := UpdateHint(opts.HolidayCacheValid); hint != "" {
+		_, err = fmt.Fprintln(opts.Writer, "\n"+hint)
 	}
 	return err
 }
 
+// widthReport receives the layout measurements of every grid RunPlain
+// prints; nil disables them.
+var widthReport io.Writer
+
+// SetPrintWidth makes RunPlain write the measured width of each month block,
+// the grid and the terminal to w, followed by a ruler as wide as the grid,
+// for reporting alignment problems. Pass os.Stderr to keep stdout clean, or
+// nil to turn it off.
+func SetPrintWidth(w io.Writer) {
+	widthReport = w
+}
+
+// reportWidths writes one measurement line and a ruler for a laid-out grid.
+func reportWidths(w io.Writer, blocks []MonthBlock, cols, termWidth int) {
+	widths := make([]string, len(blocks))
+	for i, block := range blocks {
+		widths[i] = strconv.Itoa(block.Width)
+	}
+	grid := GridWidth(blocks, cols)
+	fmt.Fprintf(w, "宽度: 终端 %d，网格 %d（%d 列），月份 %s\n", termWidth, grid, cols, strings.Join(widths, " "))
+	fmt.Fprintln(w, widthRuler(grid))
+}
+
+// widthRuler is a width-column ruler: "+" every 5 columns and the tens
+// digit every 10, e.g. "----+----1----+----2".
+func widthRuler(width int) string {
+	var sb strings.Builder
+	for i := 1; i <= width; i++ {
+		switch {
+		case i%10 == 0:
+			sb.WriteByte(byte('0' + i/10%10))
+		case i%5 == 0:
+			sb.WriteByte('+')
+		default:
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
+
 // DetectWidth tries to determine the terminal width, falling back to 100 cols.
 func DetectWidth() int {
 	fd := os.Stdout.Fd()
 	if isatty.IsTerminal(fd) {
 		if w, _, err := term.GetSize(int(fd)); err == nil {
+			slog.Debug("detected terminal width", "width", w)
 			return w
 		}
 	}
+	slog.Debug("terminal width unavailable, using fallback", "width", 100)
 	return 100
 }
 
-func fetchViews(svc *calendar.Service, req calendar.Request) ([]calendar.MonthView, error) {
+// yearHeader centers the title of one year of a year range over width.
+func yearHeader(header string, width int) string {
+	if !noColorMode {
+		header = titleStyle.Render(header)
+	}
+	return strings.TrimRight(textwidth.Center(header, width), " ")
+}
+
+// fiscalTitle names the fiscal year starting in month start of year, e.g.
+// "2025 财年（2025-04 ~ 2026-03）".
+func fiscalTitle(year, start int) string {
+	last := calendar.Request{Year: year, Month: start}.Add(0, 11)
+	return fmt.Sprintf("%d 财年（%d-%02d ~ %d-%02d）", year, year, start, last.Year, last.Month)
+}
+
+// fetchRange concatenates the views of every request in reqs.
+func fetchRange(svc *calendar.Service, reqs []calendar.Request, fiscalStart int) ([]calendar.MonthView, error) {
+	var views []calendar.MonthView
+	for _, req := range reqs {
+		reqViews, err := fetchViews(svc, req, fiscalStart)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, reqViews...)
+	}
+	return views, nil
+}
+
+// fetchViews returns the months of req; a year request with fiscalStart
+// after January covers the twelve months from fiscalStart.
+func fetchViews(svc *calendar.Service, req calendar.Request, fiscalStart int) ([]calendar.MonthView, error) {
 	if req.Mode == calendar.ModeYear {
+		if fiscalStart > 1 {
+			return svc.Months(req.Year, fiscalStart, 12)
+		}
 		return svc.Year(req.Year)
 	}
 	view, err := svc.Month(req.Year, req.Month)
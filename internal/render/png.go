@@ -0,0 +1,163 @@
+package render
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/bitmapfont/v3"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/textwidth"
+)
+
+// FormatPNG selects RenderPNG for a single requested month.
+const FormatPNG = "png"
+
+// DefaultPNGScale is the factor RenderPNG enlarges its pixels by unless told
+// otherwise, giving 24 pixel high Chinese characters.
+const DefaultPNGScale = 2
+
+// Grid of the embedded bitmap font before scaling: a textwidth column is
+// as wide as a halfwidth glyph, and Chinese glyphs take two.
+const (
+	pngColumn      = 6
+	pngGlyphHeight = 13
+	pngLineHeight  = 18 // glyphs plus leading
+)
+
+// ErrPNGMultipleMonths is returned when RenderPNG is asked for anything but
+// one month; year grids are not supported yet.
+var ErrPNGMultipleMonths = errors.New("png 格式目前只支持单个月份")
+
+// PNGOptions controls RenderPNG.
+type PNGOptions struct {
+	Scale int // pixel enlargement, 1 for the font's native 12 pixels; 0 means DefaultPNGScale
+}
+
+// RenderPNG draws views, which must hold exactly one month, as a PNG image
+// laid out like RenderSVG: a title, the weekday header and every day's cell
+// lines per SetLunarPosition. The text uses an embedded 12 pixel bitmap font
+// that covers Chinese, so the image looks the same wherever it is shown.
+// Every rune is placed on a grid of textwidth columns, so labels line up
+// exactly as in the terminal. Days are colored from the current palette, and
+// today is outlined. SetNoColor draws everything in one color.
+func RenderPNG(w io.Writer, views []calendar.MonthView, opts PNGOptions) error {
+	if len(views) != 1 {
+		return ErrPNGMultipleMonths
+	}
+	view := views[0]
+	scale := opts.Scale
+	if scale <= 0 {
+		scale = DefaultPNGScale
+	}
+	face := bitmapfont.FaceSC
+	ascent := face.Metrics().Ascent.Ceil()
+	cellWidth := (determineColumnWidth(view) + cellPadding*2) * pngColumn
+	margin := pngLineHeight
+
+	lineCounts := make([]int, len(view.Weeks))
+	for r, week := range view.Weeks {
+		for _, day := range week {
+			lines, _ := dayCellLines(day)
+			lineCounts[r] = max(lineCounts[r], len(lines))
+		}
+	}
+	height := margin + pngLineHeight*5/2 + margin
+	for _, count := range lineCounts {
+		height += count*pngLineHeight + pngLineHeight/2
+	}
+	width := 7*cellWidth + 2*margin
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.Draw(img, img.Bounds(), image.NewUniform(hexColor(svgBackground)), image.Point{}, xdraw.Src)
+	// text draws s with its baseline at y, one rune per textwidth column;
+	// bold strikes every glyph twice, a pixel apart.
+	text := func(x, y int, s string, c color.Color, bold bool) {
+		if strings.TrimSpace(s) == "" {
+			return
+		}
+		d := font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face}
+		column := 0
+		for _, r := range s {
+			glyph := string(r)
+			d.Dot = fixed.P(x+column*pngColumn, y)
+			d.DrawString(glyph)
+			if bold {
+				d.Dot = fixed.P(x+column*pngColumn+1, y)
+				d.DrawString(glyph)
+			}
+			column += textwidth.StringWidth(glyph)
+		}
+	}
+
+	foreground := hexColor(svgForeground)
+	y := margin + ascent
+	title := view.Title
+	text(margin+(7*cellWidth-textwidth.StringWidth(title)*pngColumn)/2, y, title, foreground, true)
+
+	y += pngLineHeight * 3 / 2
+	header := hexColor(svgHeader)
+	if noColorMode {
+		header = foreground
+	}
+	for i, name := range rotateWeekdays(weekdays, view.WeekStart) {
+		text(margin+i*cellWidth+cellPadding*pngColumn, y, name, header, true)
+	}
+
+	top := y - ascent + pngLineHeight
+	for r, week := range view.Weeks {
+		for i, day := range week {
+			lines, _ := dayCellLines(day)
+			c := foreground
+			if !noColorMode {
+				if hex := sequenceHex(dayColor(day)); hex != "" {
+					c = hexColor(hex)
+				}
+			}
+			x := margin + i*cellWidth
+			if day.InMonth && day.IsToday {
+				outline(img, image.Rect(x+pngColumn/2, top, x+cellWidth-pngColumn/2, top+len(lines)*pngLineHeight+pngLineHeight/4), c)
+			}
+			for l, line := range lines {
+				text(x+cellPadding*pngColumn, top+(pngLineHeight-pngGlyphHeight)/2+ascent+l*pngLineHeight, line, c, false)
+			}
+		}
+		top += lineCounts[r]*pngLineHeight + pngLineHeight/2
+	}
+
+	if scale > 1 {
+		scaled := image.NewRGBA(image.Rect(0, 0, width*scale, height*scale))
+		xdraw.NearestNeighbor.Scale(scaled, scaled.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+		img = scaled
+	}
+	return png.Encode(w, img)
+}
+
+// outline draws the one pixel border of rect in c.
+func outline(img *image.RGBA, rect image.Rectangle, c color.Color) {
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		img.Set(x, rect.Min.Y, c)
+		img.Set(x, rect.Max.Y-1, c)
+	}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		img.Set(rect.Min.X, y, c)
+		img.Set(rect.Max.X-1, y, c)
+	}
+}
+
+// hexColor converts "#RRGGBB" to an opaque color; anything else is black.
+func hexColor(hex string) color.RGBA {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}
+}
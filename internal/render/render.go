@@ -1,9 +1,14 @@
 package render
 
 import (
+	"context"
 	"fmt"
-	"regexp"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
@@ -15,21 +20,218 @@ import (
 const cellPadding = 1
 
 var (
-	noColorMode bool // Global flag to disable all color output
+	noColorMode      bool // Global flag to disable all color output
+	noBorderMode     bool // Global flag to drop the rounded border around months
+	showAdjacentMode bool // Global flag to fill blank cells with adjacent-month days
+	noAdjacentLunar  bool // Global flag to drop the lunar label of adjacent-month days
+	yearColumns      int  // Forced number of month columns; 0 picks by width
+	gutter           = DefaultGutter
+	dayOfYearMode    bool // Global flag to add a day-of-year row under each week
+	holidaysOnlyMode bool // Global flag to dim ordinary working days
+	holidaySummary   bool // Global flag to add MonthHolidaySummary under each month
+	noUpdateHintMode bool // Global flag to hide the stale holiday data hint
+	pentadMode       bool // Global flag to add PentadSummary below the calendar
+	denseMode        bool // Global flag to drop the blank spacer rows of month grids
+	lunarPosition    = LunarBelow
+	dayNumeral       = DayNumeralArabic
+	dayAlign         = DayAlignLeft
+	secondHeader     []string // weekday names of the second header row; nil for none
+	searchQuery      string   // names SetSearchHighlight picks out; "" for none
+	sixWeeks         = SixWeeksAuto
 )
 
+// Modes of SetSixWeeks.
+const (
+	SixWeeksAuto = "auto" // pad only when several months are rendered
+	SixWeeksOn   = "on"
+	SixWeeksOff  = "off"
+)
+
+// maxWeeks is the most week rows a month can span.
+const maxWeeks = 6
+
+// SetSixWeeks chooses when month grids are padded with blank weeks to the
+// full six rows, so months laid out side by side share one height:
+// SixWeeksAuto (the default) pads multi-month output only.
+func SetSixWeeks(mode string) error {
+	switch mode {
+	case SixWeeksAuto, SixWeeksOn, SixWeeksOff:
+		sixWeeks = mode
+		return nil
+	}
+	return fmt.Errorf("不支持的六周模式 %q，可选 auto、on 或 off", mode)
+}
+
+// Placements of the lunar label relative to the Gregorian day number.
+const (
+	LunarBelow  = "below"
+	LunarAbove  = "above"
+	LunarInline = "inline" // "18 初九" in a single row
+	LunarNone   = "none"
+)
+
+// SetLunarPosition chooses where month grids show the lunar label: one of
+// LunarBelow (the default), LunarAbove, LunarInline or LunarNone.
+func SetLunarPosition(position string) error {
+	switch position {
+	case LunarBelow, LunarAbove, LunarInline, LunarNone:
+		lunarPosition = position
+		return nil
+	}
+	return fmt.Errorf("不支持的农历位置 %q，可选 below、above、inline 或 none", position)
+}
+
+// Numeral systems for the day numbers of month grids.
+const (
+	DayNumeralArabic    = "arabic"    // " 1" … "31"
+	DayNumeralChinese   = "chinese"   // "一" … "三十一"
+	DayNumeralFullwidth = "fullwidth" // "１" … "３１"
+)
+
+// SetDayNumeral chooses how month grids write day numbers: one of
+// DayNumeralArabic (the default), DayNumeralChinese or DayNumeralFullwidth.
+func SetDayNumeral(numeral string) error {
+	switch numeral {
+	case DayNumeralArabic, DayNumeralChinese, DayNumeralFullwidth:
+		dayNumeral = numeral
+		return nil
+	}
+	return fmt.Errorf("不支持的日期数字 %q，可选 arabic、chinese 或 fullwidth", numeral)
+}
+
+// Placements of the day number within its cell for SetDayAlign.
+const (
+	DayAlignLeft   = "left"   // padded like "%2d", so " 1" sits over "初九"
+	DayAlignCenter = "center" // centered over the cell, " 12 " over "初九"
+)
+
+// SetDayAlign chooses where month grids put the day number in its cell:
+// DayAlignLeft (the default) or DayAlignCenter, which centers it over the
+// width of the widest cell line, usually the lunar label.
+func SetDayAlign(align string) error {
+	switch align {
+	case DayAlignLeft, DayAlignCenter:
+		dayAlign = align
+		return nil
+	}
+	return fmt.Errorf("不支持的日期对齐方式 %q，可选 left 或 center", align)
+}
+
+// Second-row weekday headers for SetBilingualHeader.
+const (
+	BilingualNone    = ""
+	BilingualEnglish = "en"     // "Su Mo Tu …"
+	BilingualPinyin  = "pinyin" // "ri yi er …", without tone marks so widths stay unambiguous
+)
+
+var pinyinWeekdays = []string{"ri", "yi", "er", "san", "si", "wu", "liu"}
+
+// SetBilingualHeader adds a second weekday header row under the Chinese one:
+// BilingualEnglish or BilingualPinyin, or BilingualNone (the default) for
+// the Chinese row only.
+func SetBilingualHeader(kind string) error {
+	switch kind {
+	case BilingualNone:
+		secondHeader = nil
+	case BilingualEnglish:
+		secondHeader = calWeekdays
+	case BilingualPinyin:
+		secondHeader = pinyinWeekdays
+	default:
+		return fmt.Errorf("不支持的双语表头 %q，可选 en 或 pinyin", kind)
+	}
+	return nil
+}
+
 // SetNoColor sets the global no-color flag
 func SetNoColor(disable bool) {
 	noColorMode = disable
 }
 
+// SetNoBorder sets the global no-border flag
+func SetNoBorder(disable bool) {
+	noBorderMode = disable
+}
+
+// SetYearColumns forces the number of month columns in Layout; 0 restores
+// the width-based choice.
+func SetYearColumns(n int) {
+	yearColumns = n
+}
+
+// SetGutter sets the number of spaces between months laid out side by side;
+// negative values restore DefaultGutter.
+func SetGutter(n int) {
+	if n < 0 {
+		n = DefaultGutter
+	}
+	gutter = n
+}
+
+// SetShowAdjacent sets the global flag to show adjacent-month days
+func SetShowAdjacent(enable bool) {
+	showAdjacentMode = enable
+}
+
+// SetNoLunarForAdjacent sets the global flag to show adjacent-month days
+// (see SetShowAdjacent) with their day number only, without a lunar label.
+func SetNoLunarForAdjacent(enable bool) {
+	noAdjacentLunar = enable
+}
+
+// SetDayOfYear sets the global flag to show each in-month day's ordinal
+// (1-366) in a third row under the lunar row.
+func SetDayOfYear(enable bool) {
+	dayOfYearMode = enable
+}
+
+// SetHolidaysOnly sets the global flag to dim in-month working days so that
+// holidays, 调休 days and weekends stand out. Today keeps its color.
+func SetHolidaysOnly(enable bool) {
+	holidaysOnlyMode = enable
+}
+
+// SetSearchHighlight colors the in-month days whose holiday or solar term
+// name contains query (see calendar.Day.Matches) with the search color and
+// dims all others, so that the matches of a whole year stand out. An empty
+// query restores the normal colors.
+func SetSearchHighlight(query string) {
+	searchQuery = strings.TrimSpace(query)
+}
+
+// SetHolidaySummary sets the global flag to print MonthHolidaySummary under
+// each month grid.
+func SetHolidaySummary(enable bool) {
+	holidaySummary = enable
+}
+
+// SetNoUpdateHint sets the global flag to hide the stale holiday data hint
+func SetNoUpdateHint(disable bool) {
+	noUpdateHintMode = disable
+}
+
+// SetDense sets the global flag to drop the blank row under the header and
+// between weeks, so each month takes fewer lines.
+func SetDense(enable bool) {
+	denseMode = enable
+}
+
+// SetPentad sets the global flag to name today's 候 (see PentadSummary)
+// below the calendar.
+func SetPentad(enable bool) {
+	pentadMode = enable
+}
+
+// headerHex is the color of the weekday header rows.
+const headerHex = "#A5B4FC"
+
 var (
 	titleStyle = lipgloss.NewStyle().
 			Bold(true).
 			Foreground(lipgloss.Color("#FEC260"))
 	headerStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#A5B4FC"))
+			Foreground(lipgloss.Color(headerHex))
 	cellStyle         = lipgloss.NewStyle()
 	dimCellStyle      = cellStyle.Copy().Foreground(lipgloss.Color("#6B7280"))
 	todayCellStyle    = cellStyle.Copy().Foreground(lipgloss.Color("#34D399"))
@@ -40,8 +242,21 @@ var (
 				Padding(0, 1)
 )
 
+// noteMarker is appended to the day number of days carrying a personal note.
+const noteMarker = "*"
+
 var weekdays = []string{"日", "一", "二", "三", "四", "五", "六"}
 
+// rotateWeekdays reorders names (indexed by time.Weekday) so the week begins
+// on start.
+func rotateWeekdays(names []string, start time.Weekday) []string {
+	rotated := make([]string, len(names))
+	for i := range names {
+		rotated[i] = names[(int(start)+i)%len(names)]
+	}
+	return rotated
+}
+
 // MonthBlock packages rendered lines with their visual width/height.
 type MonthBlock struct {
 	Lines  []string
@@ -52,8 +267,9 @@ type MonthBlock struct {
 // BuildBlocks converts month views into renderable blocks.
 func BuildBlocks(views []calendar.MonthView) ([]MonthBlock, error) {
 	blocks := make([]MonthBlock, len(views))
+	pad := sixWeeks == SixWeeksOn || (sixWeeks == SixWeeksAuto && len(views) > 1)
 	for i, view := range views {
-		block, err := buildMonthBlock(view)
+		block, err := buildMonthBlock(view, pad)
 		if err != nil {
 			return nil, err
 		}
@@ -62,75 +278,182 @@ func BuildBlocks(views []calendar.MonthView) ([]MonthBlock, error) {
 	return blocks, nil
 }
 
-// Layout renders blocks sequentially.
-func Layout(blocks []MonthBlock, _ int) string {
+// Year grid tuning: at most maxAutoColumns months side by side, separated by
+// DefaultGutter spaces unless SetGutter says otherwise.
+const (
+	maxAutoColumns = 3
+	DefaultGutter  = 2
+)
+
+// Layout arranges blocks in a grid of equally wide cells. The column count
+// comes from SetYearColumns when set, otherwise from how many blocks fit in
+// width (at most maxAutoColumns).
+func Layout(blocks []MonthBlock, width int) string {
 	if len(blocks) == 0 {
 		return ""
 	}
+	cols := LayoutColumns(blocks, width)
+	cellWidth := maxBlockWidth(blocks)
 	lines := make([]string, 0, len(blocks)*(blocks[0].Height+1))
-	for idx, block := range blocks {
-		lines = append(lines, block.Lines...)
-		if idx != len(blocks)-1 {
+	for start := 0; start < len(blocks); start += cols {
+		row := blocks[start:min(start+cols, len(blocks))]
+		height := 0
+		for _, block := range row {
+			height = max(height, block.Height)
+		}
+		for i := 0; i < height; i++ {
+			parts := make([]string, len(row))
+			for j, block := range row {
+				line := ""
+				if i < len(block.Lines) {
+					line = block.Lines[i]
+				}
+				if j != len(row)-1 {
+					line = padCell(line, cellWidth)
+				}
+				parts[j] = line
+			}
+			lines = append(lines, strings.TrimRight(strings.Join(parts, strings.Repeat(" ", gutter)), " "))
+		}
+		if start+cols < len(blocks) {
 			lines = append(lines, "")
 		}
 	}
 	return strings.Join(lines, "\n")
 }
 
-func buildMonthBlock(view calendar.MonthView) (MonthBlock, error) {
+// LayoutColumns reports how many blocks Layout places side by side.
+func LayoutColumns(blocks []MonthBlock, width int) int {
+	if len(blocks) <= 1 {
+		return 1
+	}
+	if yearColumns > 0 {
+		return min(yearColumns, len(blocks))
+	}
+	cellWidth := maxBlockWidth(blocks)
+	cols := (width + gutter) / (cellWidth + gutter)
+	return max(1, min(cols, maxAutoColumns, len(blocks)))
+}
+
+// GridWidth is the width Layout needs for cols columns of blocks.
+func GridWidth(blocks []MonthBlock, cols int) int {
+	return cols*maxBlockWidth(blocks) + (cols-1)*gutter
+}
+
+func maxBlockWidth(blocks []MonthBlock) int {
+	width := 0
+	for _, block := range blocks {
+		width = max(width, block.Width)
+	}
+	return width
+}
+
+// padCell pads a rendered block line to width. It measures like lipgloss,
+// which drew the borders, rather than GBK: box-drawing runes count as two
+// columns in GBK but the table was laid out with them as one.
+func padCell(line string, width int) string {
+	if diff := width - lipgloss.Width(line); diff > 0 {
+		return line + strings.Repeat(" ", diff)
+	}
+	return line
+}
+
+// buildMonthBlock renders view as a bordered table; padWeeks appends blank
+// weeks up to maxWeeks.
+func buildMonthBlock(view calendar.MonthView, padWeeks bool) (MonthBlock, error) {
 	colWidth := determineColumnWidth(view) + cellPadding*2
 	columns := make([]table.Column, len(weekdays))
-	for i, title := range weekdays {
+	for i, title := range rotateWeekdays(weekdays, view.WeekStart) {
 		columns[i] = table.Column{
 			Title: title,
 			Width: colWidth,
 		}
 	}
 
-	// Collect dates that need highlighting: today, holidays, and workdays (调休)
-	highlights := make(map[int]highlightInfo) // key: day number
-
-	for _, week := range view.Weeks {
-		for _, day := range week {
-			if !day.InMonth {
-				continue
-			}
-			dayNum := day.Date.Day()
-			lunarLabel := day.SecondaryLabel()
-			if lunarLabel == "" {
-				lunarLabel = "  "
-			}
-
-			info := highlightInfo{
-				day:        dayNum,
-				lunarLabel: lunarLabel,
-				isToday:    day.IsToday,
-			}
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		logHighlights(view)
+	}
 
-			// Check for holiday/workday
-			if day.HolidayInfo != nil {
-				info.hasHoliday = true
-				info.isHoliday = day.HolidayInfo.IsHoliday
-				highlights[dayNum] = info
-			} else if day.IsToday {
-				// Only highlight today if it's not a holiday/workday
-				highlights[dayNum] = info
+	// cellColors and cellLinks run parallel to rows so each cell can be
+	// decorated by its position once the table has been rendered.
+	rows := make([]table.Row, 0, maxWeeks*4+1)
+	cellColors := make([][]string, 0, cap(rows))
+	cellLinks := make([][]string, 0, cap(rows))
+	if secondHeader != nil {
+		// bubbles/table draws a single header line, so the second one is
+		// the first body row, colored like the header.
+		headerRow := table.Row(rotateWeekdays(secondHeader, view.WeekStart))
+		headerColors := make([]string, len(headerRow))
+		if !noColorMode {
+			for i := range headerColors {
+				headerColors[i] = headerSequence
 			}
 		}
+		rows = append(rows, headerRow)
+		cellColors = append(cellColors, headerColors)
+		cellLinks = append(cellLinks, nil)
 	}
-
-	rows := make([]table.Row, 0, len(view.Weeks)*3+1)
-	rows = append(rows, blankRow(len(weekdays)))
+	if !denseMode {
+		rows = append(rows, blankRow(len(weekdays)))
+		cellColors = append(cellColors, nil)
+		cellLinks = append(cellLinks, nil)
+	}
+	weekRows := 0
+	cellWidth := determineColumnWidth(view)
 	for weekIdx, week := range view.Weeks {
-		gregorianRow := make(table.Row, len(week))
-		lunarRow := make(table.Row, len(week))
+		var weekCells [][]string
+		rowColors := make([]string, len(week))
+		rowLinks := make([]string, len(week))
+		dateRow := 0
 		for idx, day := range week {
-			gregorianRow[idx] = styleDayCell(day, renderGregorianCell(day))
-			lunarRow[idx] = styleDayCell(day, renderLunarCell(day))
+			lines, dateLine := dayCellLines(day)
+			lines[dateLine] = alignDate(lines[dateLine], cellWidth)
+			if weekCells == nil {
+				weekCells = make([][]string, len(lines))
+				for r := range weekCells {
+					weekCells[r] = make([]string, len(week))
+				}
+				dateRow = dateLine
+			}
+			for r, line := range lines {
+				weekCells[r][idx] = styleDayCell(day, line)
+			}
+			if !noColorMode {
+				rowColors[idx] = dayColor(day)
+			}
+			if hyperlinkTemplate != "" {
+				rowLinks[idx] = HyperlinkURL(hyperlinkTemplate, day.Date)
+			}
+		}
+		weekRows = len(weekCells)
+		for r, cells := range weekCells {
+			rows = append(rows, table.Row(cells))
+			cellColors = append(cellColors, rowColors)
+			if r == dateRow {
+				cellLinks = append(cellLinks, rowLinks)
+			} else {
+				cellLinks = append(cellLinks, nil)
+			}
 		}
-		rows = append(rows, gregorianRow, lunarRow)
-		if weekIdx != len(view.Weeks)-1 {
+		if weekIdx != len(view.Weeks)-1 && !denseMode {
 			rows = append(rows, blankRow(len(week)))
+			cellColors = append(cellColors, nil)
+			cellLinks = append(cellLinks, nil)
+		}
+	}
+	if padWeeks {
+		// A padded week is its separator (none when dense) plus as many
+		// rows as a real one.
+		padRows := weekRows + 1
+		if denseMode {
+			padRows = weekRows
+		}
+		for i := len(view.Weeks); i < maxWeeks; i++ {
+			for j := 0; j < padRows; j++ {
+				rows = append(rows, blankRow(len(weekdays)))
+				cellColors = append(cellColors, nil)
+				cellLinks = append(cellLinks, nil)
+			}
 		}
 	}
 
@@ -142,16 +465,25 @@ func buildMonthBlock(view calendar.MonthView) (MonthBlock, error) {
 	t.SetStyles(tableStyles())
 	t.Blur()
 
-	var tableView string
-	if noColorMode {
-		tableView = strings.TrimRight(t.View(), "\n")
-	} else {
-		tableView = tableWrapperStyle.Render(strings.TrimRight(t.View(), "\n"))
+	// The wrapper carries both the border and its padding, so skipping it
+	// keeps the measured width below in sync with what is printed.
+	// Measure before wrapping: box-drawing border runes are ambiguous-width
+	// and may not match what lipgloss draws, so the frame size is added
+	// separately.
+	tableView := strings.TrimRight(t.View(), "\n")
+	tableWidth := textwidth.StringWidth(tableView)
+	// Colors and links are applied after rendering: bubbles/table truncates
+	// cell values containing escape sequences. They are placed by cell
+	// position rather than by searching the output for day numbers, because
+	// with --show-adjacent the same number (and lunar label) can appear twice
+	// in one grid, once for each month.
+	if !noColorMode || hyperlinkTemplate != "" {
+		tableView = decorateCells(tableView, cellColors, cellLinks, colWidth+cellPadding*2)
+	}
+	if !noColorMode && !noBorderMode {
+		tableView = tableWrapperStyle.Render(tableView)
+		tableWidth += tableWrapperStyle.GetHorizontalFrameSize()
 	}
-
-	// Apply colors after rendering to avoid width calculation issues
-	tableView = applyColors(tableView, highlights)
-	tableView = applyDimColor(tableView, view)
 
 	var title string
 	if noColorMode {
@@ -159,12 +491,17 @@ func buildMonthBlock(view calendar.MonthView) (MonthBlock, error) {
 	} else {
 		title = titleStyle.Render(view.Title)
 	}
+	title = textwidth.Center(title, tableWidth)
 	lines := append([]string{title, ""}, strings.Split(tableView, "\n")...)
 
-	width := 0
-	for _, line := range lines {
-		if w := textwidth.StringWidth(line); w > width {
-			width = w
+	width := max(tableWidth, textwidth.StringWidth(title))
+	if holidaySummary {
+		if summary := MonthHolidaySummary(view); summary != "" {
+			width = max(width, textwidth.StringWidth(summary))
+			if !noColorMode {
+				summary = helpStyle.Render(summary)
+			}
+			lines = append(lines, summary)
 		}
 	}
 
@@ -175,26 +512,186 @@ func buildMonthBlock(view calendar.MonthView) (MonthBlock, error) {
 	}, nil
 }
 
+// MonthHolidaySummary describes the holidays and 调休 workdays among the
+// in-month days of view in one line, e.g.
+// "节假日：国庆节/中秋节 10-01~10-08（连休 8 天）；调休：10-11". Each holiday break
+// (see calendar.Service.HolidayBreak) is listed once; a break that starts in
+// the month shows its full range and 连休 count, one carried over from the
+// previous month only its in-month days. It returns "" when the month has
+// neither.
+func MonthHolidaySummary(view calendar.MonthView) string {
+	days := view.Days()
+	var breaks, workdays []string
+	var last *calendar.HolidayBreak
+	for _, day := range days {
+		info, b := day.HolidayInfo, day.HolidayBreak
+		switch {
+		case info == nil:
+		case !info.IsHoliday:
+			workdays = append(workdays, day.Date.Format("01-02"))
+		case b == nil || (last != nil && last.Start.Equal(b.Start)):
+		default:
+			last = b
+			start, end := b.Start, b.End
+			if start.Before(day.Date) {
+				start = day.Date
+				if lastDay := days[len(days)-1].Date; end.After(lastDay) {
+					end = lastDay
+				}
+			}
+			entry := b.Name() + " " + start.Format("01-02")
+			if !end.Equal(start) {
+				entry += "~" + end.Format("01-02")
+			}
+			if start.Equal(b.Start) && b.Days > 1 {
+				entry += fmt.Sprintf("（连休 %d 天）", b.Days)
+			}
+			breaks = append(breaks, entry)
+		}
+	}
+
+	var parts []string
+	if len(breaks) > 0 {
+		parts = append(parts, "节假日："+strings.Join(breaks, "、"))
+	}
+	if len(workdays) > 0 {
+		parts = append(parts, "调休："+strings.Join(workdays, ","))
+	}
+	return strings.Join(parts, "；")
+}
+
 func determineColumnWidth(view calendar.MonthView) int {
 	width := 4
 	for _, week := range view.Weeks {
 		for _, day := range week {
-			width = max(width, textwidth.StringWidth(renderGregorianCell(day)))
-			width = max(width, textwidth.StringWidth(renderLunarCell(day)))
+			width = max(width, textwidth.StringWidth(renderDateCell(day)))
+			if lunarPosition == LunarBelow || lunarPosition == LunarAbove {
+				width = max(width, textwidth.StringWidth(renderLunarCell(day)))
+			}
+			if dayOfYearMode {
+				width = max(width, textwidth.StringWidth(renderDayOfYearCell(day)))
+			}
 		}
 	}
 	return width
 }
 
+// CellOptions tunes RenderDayCell.
+type CellOptions struct {
+	// Width pads every line of the cell to this many columns; 0 keeps each
+	// line at its natural width.
+	Width int
+}
+
+// RenderDayCell renders day the way month grids draw it: the day number and
+// lunar label placed per SetLunarPosition, the day-of-year row when enabled,
+// and the highlight color and hyperlink the grid would apply. Lines are
+// joined with "\n", so embedders can lay cells out in their own grids.
+func RenderDayCell(day calendar.Day, opts CellOptions) string {
+	lines, dateLine := dayCellLines(day)
+	color := ""
+	if !noColorMode {
+		color = dayColor(day)
+	}
+	for i, line := range lines {
+		if i == dateLine {
+			line = alignDate(line, opts.Width)
+		}
+		if opts.Width > 0 {
+			line = textwidth.PadRight(line, opts.Width)
+		}
+		link := ""
+		if i == dateLine && hyperlinkTemplate != "" && line != "" {
+			link = HyperlinkURL(hyperlinkTemplate, day.Date)
+		}
+		lines[i] = wrapCell(line, color, link)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dayCellLines returns the unstyled lines of day's cell in display order,
+// and the index of the line carrying the day number. Every day yields the
+// same number of lines under the current settings.
+func dayCellLines(day calendar.Day) ([]string, int) {
+	var lines []string
+	dateLine := 0
+	switch lunarPosition {
+	case LunarAbove:
+		lines = []string{renderLunarCell(day), renderDateCell(day)}
+		dateLine = 1
+	case LunarInline, LunarNone:
+		lines = []string{renderDateCell(day)}
+	default:
+		lines = []string{renderDateCell(day), renderLunarCell(day)}
+	}
+	if dayOfYearMode {
+		lines = append(lines, renderDayOfYearCell(day))
+	}
+	return lines, dateLine
+}
+
 func renderGregorianCell(day calendar.Day) string {
-	if !day.InMonth {
+	if !day.InMonth && !showAdjacentMode {
 		return ""
 	}
-	return fmt.Sprintf("%2d", day.Date.Day())
+	if day.Note != "" {
+		return formatDayNumber(day.Date.Day()) + noteMarker
+	}
+	return formatDayNumber(day.Date.Day())
+}
+
+var chineseDigits = []string{"", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+
+// formatDayNumber writes n (1-31) in the dayNumeral system, right-aligned
+// to the width of that system's widest day number so the digits line up.
+func formatDayNumber(n int) string {
+	switch dayNumeral {
+	case DayNumeralChinese:
+		var s string
+		switch {
+		case n < 10:
+			s = chineseDigits[n]
+		case n < 20:
+			s = "十" + chineseDigits[n%10]
+		default:
+			s = chineseDigits[n/10] + "十" + chineseDigits[n%10]
+		}
+		return textwidth.PadLeft(s, 6)
+	case DayNumeralFullwidth:
+		var sb strings.Builder
+		for _, r := range strconv.Itoa(n) {
+			sb.WriteRune(r - '0' + '０')
+		}
+		return textwidth.PadLeft(sb.String(), 4)
+	}
+	return fmt.Sprintf("%2d", n)
+}
+
+// alignDate places the date line of a cell within width columns according
+// to SetDayAlign. Left alignment keeps the line as is.
+func alignDate(line string, width int) string {
+	if dayAlign != DayAlignCenter || line == "" {
+		return line
+	}
+	return textwidth.Center(strings.TrimLeft(line, " "), width)
+}
+
+// renderDateCell is the Gregorian cell, followed by the lunar label when
+// it is shown inline.
+func renderDateCell(day calendar.Day) string {
+	gregorian := renderGregorianCell(day)
+	if lunarPosition != LunarInline || gregorian == "" {
+		return gregorian
+	}
+	lunar := renderLunarCell(day)
+	if lunar == "" {
+		return gregorian
+	}
+	return gregorian + " " + lunar
 }
 
 func renderLunarCell(day calendar.Day) string {
-	if !day.InMonth {
+	if !day.InMonth && (!showAdjacentMode || noAdjacentLunar) {
 		return ""
 	}
 	label := day.SecondaryLabel()
@@ -204,6 +701,14 @@ func renderLunarCell(day calendar.Day) string {
 	return label
 }
 
+// renderDayOfYearCell shows the ordinal of an in-month day as "#332".
+func renderDayOfYearCell(day calendar.Day) string {
+	if !day.InMonth {
+		return ""
+	}
+	return fmt.Sprintf("#%d", day.Date.YearDay())
+}
+
 func styleDayCell(day calendar.Day, content string) string {
 	if content == "" {
 		return ""
@@ -233,182 +738,365 @@ func tableStyles() table.Styles {
 	return styles
 }
 
-// highlightInfo contains information about a date that needs highlighting
-type highlightInfo struct {
-	day        int
-	lunarLabel string
-	hasHoliday bool // true if HolidayInfo is not nil
-	isHoliday  bool // true for holiday, false for workday (调休)
-	isToday    bool
+// colorEnd resets the color set by a palette sequence.
+const colorEnd = "\x1b[0m"
+
+// headerSequence is headerStyle (bold headerHex) as a palette sequence, for
+// the second header row.
+var headerSequence = func() string {
+	seq, _ := foregroundSequence(headerHex)
+	return "\x1b[1;" + strings.TrimPrefix(seq, "\x1b[")
+}()
+
+// dayColor returns the color sequence both cells of day are drawn with, or
+// "" for none.
+func dayColor(day calendar.Day) string {
+	color, _ := dayCategory(day)
+	return color
 }
 
-// applyColors adds colors to dates in the rendered table
-// Priority: holiday/workday colors > today's green
-func applyColors(output string, highlights map[int]highlightInfo) string {
-	// If no-color mode is enabled, skip all coloring
-	if noColorMode {
-		return output
-	}
-
-	// Color codes
-	holidayStart := "\x1b[38;2;59;130;246m" // Blue for holidays
-	workdayStart := "\x1b[38;2;249;115;22m" // Orange for workdays (调休)
-	todayStart := "\x1b[38;2;52;211;153m"   // Green for today
-	colorEnd := "\x1b[0m"
-
-	// Process each highlighted date
-	// Sort by day number in descending order to match two-digit numbers before single-digit ones
-	// This prevents partial matches (e.g., matching "1" in "11")
-	dayNums := make([]int, 0, len(highlights))
-	for dayNum := range highlights {
-		dayNums = append(dayNums, dayNum)
-	}
-	// Sort descending
-	for i := 0; i < len(dayNums)-1; i++ {
-		for j := i + 1; j < len(dayNums); j++ {
-			if dayNums[i] < dayNums[j] {
-				dayNums[i], dayNums[j] = dayNums[j], dayNums[i]
-			}
+// dayCategory returns the color of day and the legend label explaining it,
+// or "" for either. Priority: adjacent-month dim > marked > holiday/workday
+// > today > weekend, and in holidays-only mode any other day is dimmed.
+func dayCategory(day calendar.Day) (color, label string) {
+	switch {
+	case !day.InMonth:
+		if showAdjacentMode {
+			return colors.adjacent, "相邻月份"
+		}
+		return "", ""
+	case searchQuery != "":
+		if day.Matches(searchQuery) {
+			return colors.search, "搜索结果"
 		}
+		return colors.adjacent, ""
+	case day.Marked && colors.marked != "":
+		return colors.marked, "标记"
+	case day.HolidayInfo != nil && day.HolidayInfo.IsHoliday:
+		return holidayColor(day.HolidayInfo)
+	case day.HolidayInfo != nil:
+		return colors.workday, "调休日"
+	case day.IsToday:
+		return colors.today, "今天"
+	case day.IsWeekend:
+		switch day.Date.Weekday() {
+		case time.Saturday:
+			return colors.saturday, "周六"
+		case time.Sunday:
+			return colors.sunday, "周日"
+		}
+		return colors.weekend, "周末"
+	case holidaysOnlyMode:
+		return colors.adjacent, "工作日"
 	}
+	return "", ""
+}
 
-	// First pass: highlight all Gregorian dates
-	for _, dayNum := range dayNums {
-		info := highlights[dayNum]
-		dayStr := fmt.Sprintf("%d", dayNum)
-		var colorStart string
+func logHighlights(view calendar.MonthView) {
+	var holidayDays, workdayDays []int
+	today := 0
+	for _, day := range view.Days() {
+		switch {
+		case day.HolidayInfo != nil && day.HolidayInfo.IsHoliday:
+			holidayDays = append(holidayDays, day.Date.Day())
+		case day.HolidayInfo != nil:
+			workdayDays = append(workdayDays, day.Date.Day())
+		}
+		if day.IsToday {
+			today = day.Date.Day()
+		}
+	}
+	sort.Ints(holidayDays)
+	sort.Ints(workdayDays)
+	slog.Debug("month highlights", "month", view.Title, "holidays", holidayDays, "workdays", workdayDays, "today", today)
+}
 
-		// Determine color: holiday/workday takes priority over today
-		if info.hasHoliday {
-			if info.isHoliday {
-				colorStart = holidayStart // Blue for holidays
-			} else {
-				colorStart = workdayStart // Orange for workdays (调休)
-			}
-		} else if info.isToday {
-			colorStart = todayStart // Green for today (only if not holiday/workday)
-		} else {
+// decorateCells colors and links the body cells of a rendered table.
+// colors[r][c] is the sequence and links[r][c] the hyperlink URL for column
+// c of row r ("" or a nil row leaves cells alone); every body cell occupies
+// exactly cellWidth columns, and the header line is skipped.
+func decorateCells(output string, colors, links [][]string, cellWidth int) string {
+	lines := strings.Split(output, "\n")
+	for r, rowColors := range colors {
+		idx := r + 1 // line 0 is the header
+		if idx >= len(lines) {
+			continue
+		}
+		var rowLinks []string
+		if r < len(links) {
+			rowLinks = links[r]
+		}
+		if rowColors == nil && rowLinks == nil {
 			continue
 		}
+		cells := splitCells(lines[idx], cellWidth)
+		for c := range cells {
+			var color, link string
+			if c < len(rowColors) {
+				color = rowColors[c]
+			}
+			if c < len(rowLinks) {
+				link = rowLinks[c]
+			}
+			cells[c] = wrapCell(cells[c], color, link)
+		}
+		lines[idx] = strings.Join(cells, "")
+	}
+	return strings.Join(lines, "\n")
+}
 
-		// Highlight the Gregorian date number
-		// For single-digit numbers (1-9), match with leading space: " 1", " 2", etc.
-		// For two-digit numbers (10-31), match the full number: "10", "11", etc.
-		var pattern string
-		if dayNum < 10 {
-			// Single digit: must have leading space to avoid matching part of two-digit numbers
-			pattern = fmt.Sprintf(`(\s+)%s(\s+|│)`, regexp.QuoteMeta(dayStr))
-		} else {
-			// Two digits: match full number, can have leading space or table border
-			pattern = fmt.Sprintf(`(\s|│)%s(\s+|│)`, regexp.QuoteMeta(dayStr))
+// splitCells cuts a plain line into consecutive cellWidth-wide pieces.
+func splitCells(line string, cellWidth int) []string {
+	var cells []string
+	var current strings.Builder
+	width := 0
+	for _, r := range line {
+		current.WriteRune(r)
+		width += textwidth.StringWidth(string(r))
+		if width >= cellWidth {
+			cells = append(cells, current.String())
+			current.Reset()
+			width = 0
 		}
-		replacement := fmt.Sprintf("${1}%s%s%s${2}", colorStart, dayStr, colorEnd)
-		re := regexp.MustCompile(pattern)
-		output = re.ReplaceAllString(output, replacement)
 	}
+	if current.Len() > 0 {
+		cells = append(cells, current.String())
+	}
+	return cells
+}
 
-	// Second pass: highlight lunar labels
-	// Use line-based approach to ensure lunar labels are colored in the correct column
-	lines := strings.Split(output, "\n")
-	coloredLunarLabels := make(map[string]bool) // Track which date's lunar label we've colored
+// wrapCell colors and links the non-blank part of cell, keeping its padding
+// plain. Empty color or link skips that decoration.
+func wrapCell(cell, color, link string) string {
+	core := strings.TrimSpace(cell)
+	if core == "" || (color == "" && link == "") {
+		return cell
+	}
+	start := strings.Index(cell, core)
+	decorated := core
+	if link != "" {
+		decorated = hyperlink(link, decorated)
+	}
+	if color != "" {
+		decorated = color + decorated + colorEnd
+	}
+	return cell[:start] + decorated + cell[start+len(core):]
+}
 
-	for _, dayNum := range dayNums {
-		info := highlights[dayNum]
-		var colorStart string
+// UpdateHint returns the reminder to run lucal -u when the holiday cache is
+// missing or stale, or "" when cacheValid or the hint is disabled.
+func UpdateHint(cacheValid bool) string {
+	if cacheValid || noUpdateHintMode {
+		return ""
+	}
+	return "尚未下载节假日数据或节假日数据超过 6 个月未更新，运行  lucal -u 获取最新数据"
+}
 
-		// Determine color: holiday/workday takes priority over today
-		if info.hasHoliday {
-			if info.isHoliday {
-				colorStart = holidayStart // Blue for holidays
-			} else {
-				colorStart = workdayStart // Orange for workdays (调休)
-			}
-		} else if info.isToday {
-			colorStart = todayStart // Green for today (only if not holiday/workday)
-		} else {
-			continue
+// helpSeparator joins the entries of the help line.
+const helpSeparator = "  "
+
+// HelpLine describes the interactive key bindings; jumpStep is the number
+// of months ctrl+f/ctrl+b move by. When the full text is wider than width it
+// switches to an abbreviated form, wrapped between entries if even that does
+// not fit. width <= 0 means unknown and keeps the full text.
+func HelpLine(jumpStep, width int) string {
+	full := []string{
+		"j/] 下个月", "k/[ 上个月", "J/} 下一年", "K/{ 上一年",
+		fmt.Sprintf("^F/^B 前进/后退 %d 个月", jumpStep),
+		". 回到当前月", "y 输入年份", "m 输入月份", "/ 搜索节日", "f 高亮年内匹配", "^O 返回上次跳转前", "t 节气表", "W 切换每周首日", "PgUp/PgDn 滚动", "q 退出",
+	}
+	helpText := strings.Join(full, helpSeparator)
+	if width > 0 && textwidth.StringWidth(helpText) > width {
+		short := []string{"导航 j/k/J/K ^F/^B", ". 今天", "y/m 跳转", "/f 搜索", "^O 返回", "t 节气", "W 周首", "q 退出"}
+		helpText = wrapEntries(short, width)
+	}
+	if noColorMode {
+		return helpText
+	}
+	return helpStyle.Render(helpText)
+}
+
+// wrapEntries joins entries with helpSeparator, starting a new line before
+// an entry that would push the current line past width.
+func wrapEntries(entries []string, width int) string {
+	var lines []string
+	line := ""
+	for _, entry := range entries {
+		switch {
+		case line == "":
+			line = entry
+		case textwidth.StringWidth(line+helpSeparator+entry) > width:
+			lines = append(lines, line)
+			line = entry
+		default:
+			line += helpSeparator + entry
 		}
+	}
+	return strings.Join(append(lines, line), "\n")
+}
 
-		// Create a unique key for this date's lunar label
-		lunarKey := fmt.Sprintf("%d:%s", dayNum, info.lunarLabel)
-		if coloredLunarLabels[lunarKey] {
-			continue // Already colored this specific date's lunar label
-		}
-
-		// Find the line containing the colored Gregorian date
-		dayStr := fmt.Sprintf("%d", dayNum)
-		coloredDatePattern := fmt.Sprintf("%s%s%s", colorStart, dayStr, colorEnd)
-
-		for i := 0; i < len(lines)-1; i++ {
-			if strings.Contains(lines[i], coloredDatePattern) {
-				// This line contains the colored date, check the next line for lunar labels
-				nextLine := lines[i+1]
-
-				// Find all occurrences of this lunar label in the next line
-				escapedLunar := regexp.QuoteMeta(info.lunarLabel)
-				lunarPattern := fmt.Sprintf(`(\s|│)(%s)(\s+|│)`, escapedLunar)
-				lunarRe := regexp.MustCompile(lunarPattern)
-
-				// Replace only the first occurrence (should be in the correct column)
-				if loc := lunarRe.FindStringIndex(nextLine); loc != nil {
-					before := nextLine[:loc[0]]
-					matched := nextLine[loc[0]:loc[1]]
-					after := nextLine[loc[1]:]
-
-					// Extract the parts from the matched string
-					parts := lunarRe.FindStringSubmatch(matched)
-					if len(parts) >= 4 {
-						// parts[0] = full match
-						// parts[1] = first capture group (\s|│)
-						// parts[2] = second capture group (lunar label)
-						// parts[3] = third capture group (\s+|│)
-						coloredMatch := parts[1] + colorStart + info.lunarLabel + colorEnd + parts[3]
-						lines[i+1] = before + coloredMatch + after
-						coloredLunarLabels[lunarKey] = true
-						break
-					}
-				}
+// NotesSummary lists the personal notes of the in-month days of views, one
+// "MM-DD 标签" entry each. Days that are also holidays mention the holiday so
+// both annotations stay visible. It returns "" when there are no notes.
+func NotesSummary(views []calendar.MonthView) string {
+	entries := make([]string, 0)
+	for _, view := range views {
+		for _, day := range view.Days() {
+			if day.Note == "" {
+				continue
+			}
+			entry := day.Date.Format("01-02") + " " + day.Note
+			if day.HolidayInfo != nil && day.HolidayInfo.Name != "" {
+				entry += "（" + day.HolidayInfo.Name + "）"
 			}
+			entries = append(entries, entry)
 		}
 	}
+	if len(entries) == 0 {
+		return ""
+	}
+	summary := noteMarker + " 备注：" + strings.Join(entries, "  ")
+	if noColorMode {
+		return summary
+	}
+	return helpStyle.Render(summary)
+}
 
-	output = strings.Join(lines, "\n")
-
-	return output
+// MarksSummary lists the marked in-month days of views, one "MM-DD 周二"
+// entry each, naming the holiday of days that have one. It returns "" when
+// nothing is marked.
+func MarksSummary(views []calendar.MonthView) string {
+	entries := make([]string, 0)
+	for _, view := range views {
+		for _, day := range view.Days() {
+			if !day.Marked {
+				continue
+			}
+			entry := day.Date.Format("01-02") + " 周" + weekdays[day.Date.Weekday()]
+			if day.HolidayInfo != nil && day.HolidayInfo.Name != "" {
+				entry += "（" + day.HolidayInfo.Name + "）"
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	summary := "标记：" + strings.Join(entries, "  ")
+	if noColorMode {
+		return summary
+	}
+	return helpStyle.Render(summary)
 }
 
-// abs returns the absolute value of an integer
-func abs(x int) int {
-	if x < 0 {
-		return -x
+// WorkdaySummary lists the 调休 workdays among the in-month days of views
+// whose holiday data names the holiday they belong to, one
+// "MM-DD 为国庆节调休，节前上班" entry each. It returns "" when there are none.
+func WorkdaySummary(views []calendar.MonthView) string {
+	entries := make([]string, 0)
+	for _, view := range views {
+		for _, day := range view.Days() {
+			if day.HolidayInfo == nil {
+				continue
+			}
+			if adjustment := day.HolidayInfo.Adjustment(); adjustment != "" {
+				entries = append(entries, day.Date.Format("01-02")+" "+adjustment)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	summary := "调休：" + strings.Join(entries, "  ")
+	if noColorMode {
+		return summary
 	}
-	return x
+	return helpStyle.Render(summary)
 }
 
-// applyDimColor adds gray color to dates outside the current month
-func applyDimColor(output string, view calendar.MonthView) string {
-	// This is more complex - we'd need to track which dates are outside the month
-	// For now, we'll skip this to avoid complexity
-	// The dim color can be applied at the cell level if needed
-	return output
+// AlmanacSummary describes today's 宜/忌 when today is one of the in-month
+// days of views and almanac data covers it. It returns "" otherwise.
+func AlmanacSummary(views []calendar.MonthView) string {
+	for _, view := range views {
+		for _, day := range view.Days() {
+			if !day.IsToday || (len(day.Yi) == 0 && len(day.Ji) == 0) {
+				continue
+			}
+			parts := []string{"今日 " + day.LunarDateStringWithYear()}
+			if len(day.Yi) > 0 {
+				parts = append(parts, "宜："+strings.Join(day.Yi, " "))
+			}
+			if len(day.Ji) > 0 {
+				parts = append(parts, "忌："+strings.Join(day.Ji, " "))
+			}
+			summary := strings.Join(parts, "  ")
+			if noColorMode {
+				return summary
+			}
+			return helpStyle.Render(summary)
+		}
+	}
+	return ""
 }
 
-// HelpLine describes the interactive key bindings.
-func HelpLine() string {
-	helpText := "j/] 下个月  k/[ 上个月  J/} 下一年  K/{ 上一年 . 回到当前月  y 输入年份  m 输入月份  q 退出"
-	if noColorMode {
-		return helpText
+// PentadSummary names the 候 of the 七十二候 today falls in, e.g.
+// "今日物候：蚯蚓结", when pentads are enabled and today is one of the
+// in-month days of views. It returns "" otherwise.
+func PentadSummary(views []calendar.MonthView) string {
+	if !pentadMode {
+		return ""
 	}
-	return helpStyle.Render(helpText)
+	for _, view := range views {
+		for _, day := range view.Days() {
+			if !day.IsToday || day.Pentad() == "" {
+				continue
+			}
+			summary := "今日物候：" + day.Pentad()
+			if noColorMode {
+				return summary
+			}
+			return helpStyle.Render(summary)
+		}
+	}
+	return ""
 }
 
-// ColorLegend returns a legend explaining the color coding for holidays.
-func ColorLegend() string {
-	legend := "\n蓝色=节假日  橙色=调休日"
+// ColorLegend explains the colors that views actually use, each label drawn
+// in its own color, e.g. "节假日  调休日  今天" with a leading newline.
+// Labels sharing a color are joined, like "周六/周日" under a weekend theme.
+// Entries wrap to width (<= 0 keeps one line). It returns "" when nothing is
+// colored, which includes no-color mode.
+func ColorLegend(views []calendar.MonthView, width int) string {
 	if noColorMode {
-		return legend
+		return ""
+	}
+	var order []string              // colors in order of first use
+	labels := map[string][]string{} // labels by color
+	for _, view := range views {
+		for _, week := range view.Weeks {
+			for _, day := range week {
+				color, label := dayCategory(day)
+				if color == "" || label == "" {
+					continue
+				}
+				if _, seen := labels[color]; !seen {
+					order = append(order, color)
+				}
+				if !slices.Contains(labels[color], label) {
+					labels[color] = append(labels[color], label)
+				}
+			}
+		}
+	}
+	if len(order) == 0 {
+		return ""
+	}
+	entries := make([]string, len(order))
+	for i, color := range order {
+		entries[i] = color + strings.Join(labels[color], "/") + colorEnd
+	}
+	if width <= 0 {
+		return "\n" + strings.Join(entries, helpSeparator)
 	}
-	// Use gray color for the legend
-	legendStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
-	return legendStyle.Render(legend)
+	return "\n" + wrapEntries(entries, width)
 }
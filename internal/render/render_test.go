@@ -1,10 +1,23 @@
 package render
 
 import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/lululau/lucal/internal/almanac"
 	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/holidays"
+	"github.com/lululau/lucal/internal/holidays/holidaystest"
+	"github.com/lululau/lucal/internal/textwidth"
 )
 
 func TestMonthBlockContainsLunarLabels(t *testing.T) {
@@ -23,3 +36,1491 @@ func TestMonthBlockContainsLunarLabels(t *testing.T) {
 		t.Fatalf("expected lunar labels in layout, got:\n%s", output)
 	}
 }
+
+func TestNoBorderSkipsWrapper(t *testing.T) {
+	SetNoBorder(true)
+	defer SetNoBorder(false)
+
+	svc := calendar.NewService()
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	if strings.ContainsAny(output, "╭╮╰╯│") {
+		t.Fatalf("expected no border characters, got:\n%s", output)
+	}
+}
+
+func TestRenderJSONListsInMonthDays(t *testing.T) {
+	svc := calendar.NewService()
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, []calendar.MonthView{view}, JSONOptions{}); err != nil {
+		t.Fatalf("RenderJSON failed: %v", err)
+	}
+	var out jsonOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(out.Months) != 1 || len(out.Months[0].Days) != 30 {
+		t.Fatalf("expected one month with 30 days, got %+v", out.Months)
+	}
+	if first := out.Months[0].Days[0]; first.Date != "2025-11-01" || first.LunarDate == "" {
+		t.Fatalf("unexpected first day: %+v", first)
+	}
+}
+
+func TestRenderJSONCompactStreamsMonths(t *testing.T) {
+	svc := calendar.NewService(calendar.WithWeekStart(time.Monday))
+	views, err := svc.Year(2025)
+	if err != nil {
+		t.Fatalf("Year failed: %v", err)
+	}
+	generated := time.Date(2025, 11, 11, 9, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err = RenderJSON(&buf, views, JSONOptions{
+		Compact:      true,
+		Request:      calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeYear},
+		GeneratedAt:  generated,
+		HolidayYears: &holidays.YearInfo{MinYear: 2024, MaxYear: 2026},
+	})
+	if err != nil {
+		t.Fatalf("RenderJSON failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 12 {
+		t.Fatalf("expected one line per month, got %d", len(lines))
+	}
+	var out jsonOutput
+	if err := json.Unmarshal([]byte(lines[11]), &out); err != nil {
+		t.Fatalf("invalid JSON line: %v\n%s", err, lines[11])
+	}
+	want := jsonMeta{
+		Request:      jsonRequest{Year: 2025, Mode: "year"},
+		GeneratedAt:  "2025-11-11T09:00:00Z",
+		WeekStart:    1,
+		HolidayYears: &jsonYearRange{Min: 2024, Max: 2026},
+	}
+	if out.Meta.Request != want.Request || out.Meta.GeneratedAt != want.GeneratedAt ||
+		out.Meta.WeekStart != want.WeekStart || *out.Meta.HolidayYears != *want.HolidayYears {
+		t.Fatalf("unexpected meta %+v", out.Meta)
+	}
+	if len(out.Months) != 1 || out.Months[0].Month != 12 {
+		t.Fatalf("expected December on the last line, got %+v", out.Months)
+	}
+}
+
+func TestRenderJSONISOWeek(t *testing.T) {
+	svc := calendar.NewService()
+	dec2024, err := svc.Month(2024, 12)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	jan2021, err := svc.Month(2021, 1)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	decode := func(opts JSONOptions) jsonOutput {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := RenderJSON(&buf, []calendar.MonthView{dec2024, jan2021}, opts); err != nil {
+			t.Fatalf("RenderJSON failed: %v", err)
+		}
+		var out jsonOutput
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return out
+	}
+
+	if day := decode(JSONOptions{}).Months[0].Days[29]; day.ISOWeek != 0 || day.ISOWeekYear != 0 {
+		t.Fatalf("expected no ISO week without the option, got %+v", day)
+	}
+	out := decode(JSONOptions{ISOWeek: true})
+	tests := []struct {
+		day            jsonDay
+		week, weekYear int
+	}{
+		{out.Months[0].Days[29], 1, 2025},  // 2024-12-30, a Monday
+		{out.Months[0].Days[27], 52, 2024}, // 2024-12-28
+		{out.Months[1].Days[2], 53, 2020},  // 2021-01-03, a Sunday
+		{out.Months[1].Days[3], 1, 2021},   // 2021-01-04
+	}
+	for _, tt := range tests {
+		if tt.day.ISOWeek != tt.week || tt.day.ISOWeekYear != tt.weekYear {
+			t.Fatalf("%s: iso week %d-W%02d, want %d-W%02d", tt.day.Date, tt.day.ISOWeekYear, tt.day.ISOWeek, tt.weekYear, tt.week)
+		}
+	}
+}
+
+func TestParseDateFormat(t *testing.T) {
+	for value, want := range map[string]string{
+		"iso":        "2006-01-02",
+		"slash":      "2006/01/02",
+		"compact":    "20060102",
+		"02.01.2006": "02.01.2006",
+	} {
+		got, err := ParseDateFormat(value)
+		if err != nil || got != want {
+			t.Fatalf("ParseDateFormat(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"2006-01", "Jan 2", "yyyy-mm-dd"} {
+		if _, err := ParseDateFormat(value); err == nil {
+			t.Fatalf("ParseDateFormat(%q) should fail", value)
+		}
+	}
+
+	svc := calendar.NewService()
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, []calendar.MonthView{view}, JSONOptions{DateFormat: "20060102"}); err != nil {
+		t.Fatalf("RenderJSON failed: %v", err)
+	}
+	var out jsonOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got := out.Months[0].Days[27].Date; got != "20251128" {
+		t.Fatalf("expected compact date 20251128, got %q", got)
+	}
+}
+
+func TestRenderCalStyleMonth(t *testing.T) {
+	svc := calendar.NewService()
+	req := calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeMonth}
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := RenderCalStyle(&buf, req, []calendar.MonthView{view}); err != nil {
+		t.Fatalf("RenderCalStyle failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("expected 8 lines like cal(1), got %d:\n%s", len(lines), buf.String())
+	}
+	want := []string{
+		"   November 2025    ",
+		"Su Mo Tu We Th Fr Sa",
+		"                   1",
+		" 2  3  4  5  6  7  8",
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Fatalf("line %d=%q want %q", i, lines[i], w)
+		}
+	}
+}
+
+func TestShowAdjacentDimsNeighbouringDays(t *testing.T) {
+	svc := calendar.NewService()
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	if output := Layout(blocks, 120); strings.Count(output, "26") != 1 {
+		t.Fatalf("expected adjacent days hidden by default, got:\n%s", output)
+	}
+
+	SetShowAdjacent(true)
+	defer SetShowAdjacent(false)
+	blocks, err = BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	// November 2025 starts on a Saturday, so October 26 fills the first cell.
+	if !strings.Contains(output, colors.adjacent+"26"+colorEnd) {
+		t.Fatalf("expected dimmed October 26, got:\n%q", output)
+	}
+
+	SetNoColor(true)
+	defer SetNoColor(false)
+	first := view.Weeks[0][0]
+	if cell := RenderDayCell(first, CellOptions{}); cell != "26\n初六" {
+		t.Fatalf("expected October 26 with its lunar label, got %q", cell)
+	}
+	SetNoLunarForAdjacent(true)
+	defer SetNoLunarForAdjacent(false)
+	if cell := RenderDayCell(first, CellOptions{}); cell != "26\n" {
+		t.Fatalf("expected October 26 without a lunar label, got %q", cell)
+	}
+}
+
+func TestDayOfYearRow(t *testing.T) {
+	svc := calendar.NewService()
+	view, err := svc.Month(2024, 12)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	SetDayOfYear(true)
+	defer SetDayOfYear(false)
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	// 2024 is a leap year, so December 31 is day 366.
+	for _, want := range []string{"#336", "#366"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %s in output:\n%s", want, output)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, []calendar.MonthView{view}, JSONOptions{}); err != nil {
+		t.Fatalf("RenderJSON failed: %v", err)
+	}
+	var out jsonOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got := out.Months[0].Days[30].DayOfYear; got != 366 {
+		t.Fatalf("expected day_of_year 366 for 2024-12-31, got %d", got)
+	}
+}
+
+func TestLunarPosition(t *testing.T) {
+	svc := calendar.NewService()
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	defer SetLunarPosition(LunarBelow)
+
+	render := func(position string) []string {
+		t.Helper()
+		if err := SetLunarPosition(position); err != nil {
+			t.Fatalf("SetLunarPosition(%q) failed: %v", position, err)
+		}
+		blocks, err := BuildBlocks([]calendar.MonthView{view})
+		if err != nil {
+			t.Fatalf("BuildBlocks failed: %v", err)
+		}
+		return strings.Split(Layout(blocks, 120), "\n")
+	}
+	// Line 4 is the first week; November 1 2025 is 农历十二.
+	if lines := render(LunarAbove); !strings.Contains(lines[4], "十二") || !strings.Contains(lines[5], "1") {
+		t.Fatalf("expected the lunar row above the date, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if lines := render(LunarInline); !strings.Contains(lines[4], " 1 十二") || !strings.Contains(lines[10], "18 廿九") {
+		t.Fatalf("expected inline lunar labels, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if output := strings.Join(render(LunarNone), "\n"); strings.Contains(output, "十二") {
+		t.Fatalf("expected no lunar labels, got:\n%s", output)
+	}
+	if err := SetLunarPosition("left"); err == nil {
+		t.Fatal("expected an error for an unknown position")
+	}
+}
+
+func TestDayNumeral(t *testing.T) {
+	svc := calendar.NewService()
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	defer SetDayNumeral(DayNumeralArabic)
+
+	if err := SetDayNumeral(DayNumeralChinese); err != nil {
+		t.Fatalf("SetDayNumeral failed: %v", err)
+	}
+	tests := []struct {
+		day  int
+		want string
+	}{
+		{1, "    一"},
+		{10, "    十"},
+		{15, "  十五"},
+		{20, "  二十"},
+		{31, "三十一"},
+	}
+	for _, tt := range tests {
+		got := formatDayNumber(tt.day)
+		if got != tt.want || textwidth.StringWidth(got) != 6 {
+			t.Fatalf("formatDayNumber(%d) = %q (width %d), want %q", tt.day, got, textwidth.StringWidth(got), tt.want)
+		}
+	}
+	if width := determineColumnWidth(view); width < 6 {
+		t.Fatalf("expected columns wide enough for 三十一, got %d", width)
+	}
+
+	if err := SetDayNumeral(DayNumeralFullwidth); err != nil {
+		t.Fatalf("SetDayNumeral failed: %v", err)
+	}
+	if got := formatDayNumber(7); got != "  ７" {
+		t.Fatalf("formatDayNumber(7) = %q, want %q", got, "  ７")
+	}
+	if got := formatDayNumber(31); got != "３１" {
+		t.Fatalf("formatDayNumber(31) = %q, want %q", got, "３１")
+	}
+	if err := SetDayNumeral("roman"); err == nil {
+		t.Fatal("expected an error for an unknown numeral system")
+	}
+}
+
+func TestBilingualHeader(t *testing.T) {
+	svc := calendar.NewService(calendar.WithWeekStart(time.Monday))
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	defer SetBilingualHeader(BilingualNone)
+
+	render := func(kind string) []string {
+		t.Helper()
+		if err := SetBilingualHeader(kind); err != nil {
+			t.Fatalf("SetBilingualHeader(%q) failed: %v", kind, err)
+		}
+		blocks, err := BuildBlocks([]calendar.MonthView{view})
+		if err != nil {
+			t.Fatalf("BuildBlocks failed: %v", err)
+		}
+		return strings.Split(Layout(blocks, 120), "\n")
+	}
+	plain := render(BilingualNone)
+	lines := render(BilingualEnglish)
+	// Line 2 is the Chinese header; the week rows move down by one.
+	if !strings.HasPrefix(strings.TrimSpace(lines[2]), "一") || strings.Fields(lines[3])[0] != "Mo" {
+		t.Fatalf("expected 一 over Mo, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if len(lines) != len(plain)+1 {
+		t.Fatalf("expected exactly one extra row, got %d lines vs %d", len(lines), len(plain))
+	}
+	if strings.Index(lines[2], "一") != strings.Index(lines[3], "Mo") {
+		t.Fatalf("expected the header rows aligned, got:\n%s\n%s", lines[2], lines[3])
+	}
+	if got := sequenceHex(headerSequence); got != headerHex || !strings.HasPrefix(headerSequence, "\x1b[1;") {
+		t.Fatalf("expected the second header in bold %s, got %q", headerHex, headerSequence)
+	}
+	if lines := render(BilingualPinyin); strings.Join(strings.Fields(lines[3]), " ") != "yi er san si wu liu ri" {
+		t.Fatalf("expected the pinyin header, got %q", lines[3])
+	}
+	if err := SetBilingualHeader("fr"); err == nil {
+		t.Fatal("expected an error for an unknown header")
+	}
+}
+
+func TestTodayColoredByCellPosition(t *testing.T) {
+	now := time.Date(2025, 11, 11, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	if strings.Count(output, colors.today) != 2 {
+		t.Fatalf("expected today's date and lunar cells colored once each, got:\n%q", output)
+	}
+	if !strings.Contains(output, colors.today+"11"+colorEnd) || !strings.Contains(output, colors.today+"廿二"+colorEnd) {
+		t.Fatalf("expected November 11 and 廿二 colored, got:\n%q", output)
+	}
+}
+
+func TestRenderDayCell(t *testing.T) {
+	now := time.Date(2025, 11, 11, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	var today calendar.Day
+	for _, day := range view.Days() {
+		if day.IsToday {
+			today = day
+		}
+	}
+
+	cell := RenderDayCell(today, CellOptions{Width: 6})
+	want := colors.today + "11" + colorEnd + "    \n" + colors.today + "廿二" + colorEnd + "  "
+	if cell != want {
+		t.Fatalf("RenderDayCell = %q, want %q", cell, want)
+	}
+
+	SetNoColor(true)
+	defer SetNoColor(false)
+	SetLunarPosition(LunarInline)
+	defer SetLunarPosition(LunarBelow)
+	if cell := RenderDayCell(today, CellOptions{}); cell != "11 廿二" {
+		t.Fatalf("inline RenderDayCell = %q, want %q", cell, "11 廿二")
+	}
+}
+
+func TestHyperlinksWrapDayNumbers(t *testing.T) {
+	now := time.Date(2025, 11, 11, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	plainBlocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+
+	SetHyperlinks("https://example.com/{date}")
+	defer SetHyperlinks("")
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	link := hyperlink("https://example.com/2025-11-11", "11")
+	if !strings.Contains(output, colors.today+link+colorEnd) {
+		t.Fatalf("expected colored link around November 11, got:\n%q", output)
+	}
+	if strings.Contains(output, "\x1b]8;;https://example.com/2025-11-11\x1b\\廿二") {
+		t.Fatalf("lunar cells should not be linked, got:\n%q", output)
+	}
+	if blocks[0].Width != plainBlocks[0].Width {
+		t.Fatalf("links changed block width: %d vs %d", blocks[0].Width, plainBlocks[0].Width)
+	}
+	for i, line := range blocks[0].Lines {
+		if textwidth.StringWidth(line) != textwidth.StringWidth(plainBlocks[0].Lines[i]) {
+			t.Fatalf("line %d width changed by links:\n%q", i, line)
+		}
+	}
+}
+
+func TestHyperlinkTerminalDetection(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"unknown", map[string]string{"TERM": "xterm-256color"}, false},
+		{"dumb", map[string]string{"TERM": "dumb", "TERM_PROGRAM": "iTerm.app"}, false},
+		{"iterm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, true},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true},
+		{"old vte", map[string]string{"VTE_VERSION": "4800"}, false},
+		{"vte", map[string]string{"VTE_VERSION": "7200"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := hyperlinkTerminal(getenv); got != tt.want {
+				t.Fatalf("hyperlinkTerminal()=%v want %v", got, tt.want)
+			}
+		})
+	}
+	if got := HyperlinkURL("https://example.com/{year}/{month}/{day}", time.Date(2025, 3, 7, 0, 0, 0, 0, time.Local)); got != "https://example.com/2025/03/07" {
+		t.Fatalf("HyperlinkURL()=%q", got)
+	}
+}
+
+func TestAlmanacSummaryShowsTodayOnly(t *testing.T) {
+	now := time.Date(2025, 11, 11, 9, 0, 0, 0, time.Local)
+	data := map[string]almanac.Entry{
+		"乙巳年九月廿二": {Yi: []string{"祭祀", "出行"}, Ji: []string{"动土"}},
+	}
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }), calendar.WithAlmanac(data))
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	want := "今日 乙巳年九月廿二  宜：祭祀 出行  忌：动土"
+	if got := AlmanacSummary([]calendar.MonthView{view}); got != want {
+		t.Fatalf("AlmanacSummary()=%q want %q", got, want)
+	}
+
+	other := calendar.NewService(calendar.WithNow(func() time.Time { return now.AddDate(0, 0, 1) }), calendar.WithAlmanac(data))
+	view, err = other.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	if got := AlmanacSummary([]calendar.MonthView{view}); got != "" {
+		t.Fatalf("expected no summary without data for today, got %q", got)
+	}
+}
+
+func TestPentadSummary(t *testing.T) {
+	now := time.Date(2025, 12, 22, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	view, err := svc.Month(2025, 12)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	if got := PentadSummary([]calendar.MonthView{view}); got != "" {
+		t.Fatalf("expected no summary unless enabled, got %q", got)
+	}
+	SetPentad(true)
+	defer SetPentad(false)
+	if got, want := PentadSummary([]calendar.MonthView{view}), "今日物候：蚯蚓结"; got != want {
+		t.Fatalf("PentadSummary()=%q want %q", got, want)
+	}
+}
+
+func TestRenderMiniWidthAndToday(t *testing.T) {
+	now := time.Date(2025, 11, 30, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	view, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	output := RenderMini(view)
+	lines := strings.Split(output, "\n")
+	if strings.TrimSpace(lines[0]) != "11月" {
+		t.Fatalf("unexpected title %q", lines[0])
+	}
+	for _, line := range lines {
+		if w := textwidth.StringWidth(line); w != MiniWidth {
+			t.Fatalf("line %q is %d columns, want %d", line, w, MiniWidth)
+		}
+	}
+	// November 30 2025 is a Sunday, the first cell of the last week.
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "[30]") {
+		t.Fatalf("expected today bracketed, got %q", last)
+	}
+}
+
+func TestLayoutColumns(t *testing.T) {
+	svc := calendar.NewService()
+	views, err := svc.Year(2025)
+	if err != nil {
+		t.Fatalf("Year failed: %v", err)
+	}
+	blocks, err := BuildBlocks(views)
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	cell := maxBlockWidth(blocks)
+	if got := LayoutColumns(blocks, 3*cell+4); got != 3 {
+		t.Fatalf("expected 3 columns when three months fit, got %d", got)
+	}
+	if got := LayoutColumns(blocks, 10*cell); got != maxAutoColumns {
+		t.Fatalf("expected auto layout capped at %d, got %d", maxAutoColumns, got)
+	}
+	if got := LayoutColumns(blocks, cell); got != 1 {
+		t.Fatalf("expected a single column on a narrow terminal, got %d", got)
+	}
+
+	SetYearColumns(2)
+	defer SetYearColumns(0)
+	if got := LayoutColumns(blocks, cell); got != 2 {
+		t.Fatalf("expected forced 2 columns, got %d", got)
+	}
+	first := strings.Split(Layout(blocks, cell), "\n")[0]
+	if !strings.Contains(first, "2025 年 1 月") || !strings.Contains(first, "2025 年 2 月") {
+		t.Fatalf("expected January and February side by side, got %q", first)
+	}
+}
+
+func TestGutter(t *testing.T) {
+	svc := calendar.NewService()
+	views, err := svc.Months(2025, 1, 2)
+	if err != nil {
+		t.Fatalf("Months failed: %v", err)
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	blocks, err := BuildBlocks(views)
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	cell := maxBlockWidth(blocks)
+
+	SetGutter(6)
+	defer SetGutter(DefaultGutter)
+	if got := GridWidth(blocks, 2); got != 2*cell+6 {
+		t.Fatalf("GridWidth=%d want %d", got, 2*cell+6)
+	}
+	if got := LayoutColumns(blocks, 2*cell+5); got != 1 {
+		t.Fatalf("expected the wider gutter to leave room for one month, got %d", got)
+	}
+	if got := LayoutColumns(blocks, 2*cell+6); got != 2 {
+		t.Fatalf("expected two months to fit with the gutter, got %d", got)
+	}
+	// The weekday rows of both months are joined on the third line; the gap
+	// between them grows by exactly the gutter.
+	gap := func() int {
+		header := strings.Split(Layout(blocks, 3*cell), "\n")[2]
+		return strings.Index(header[strings.Index(header, "六"):], "日") - len("六")
+	}
+	wide := gap()
+	SetGutter(0)
+	if got := LayoutColumns(blocks, 2*cell); got != 2 {
+		t.Fatalf("expected two months to fit without a gutter, got %d", got)
+	}
+	if narrow := gap(); wide-narrow != 6 {
+		t.Fatalf("expected the gutter to add 6 spaces, got %d vs %d", wide, narrow)
+	}
+}
+
+func TestRunPlainJSONGeneratedAtUsesServiceClock(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	now := time.Date(2025, 11, 11, 1, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err = RunPlain(PlainOptions{
+		Writer:  &buf,
+		Service: calendar.NewService(calendar.WithNow(func() time.Time { return now }), calendar.WithLocation(shanghai)),
+		Request: calendar.Request{Year: 2025, Month: 11},
+		Format:  FormatJSON,
+	})
+	if err != nil {
+		t.Fatalf("RunPlain failed: %v", err)
+	}
+	if want := `"generated_at": "2025-11-11T09:00:00+08:00"`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("expected %s in:\n%s", want, buf.String())
+	}
+}
+
+func TestRunPlainUpdateHint(t *testing.T) {
+	run := func() string {
+		var buf bytes.Buffer
+		err := RunPlain(PlainOptions{
+			Writer:  &buf,
+			Request: calendar.Request{Year: 2025, Month: 11},
+			Width:   120,
+		})
+		if err != nil {
+			t.Fatalf("RunPlain failed: %v", err)
+		}
+		return buf.String()
+	}
+	if output := run(); !strings.Contains(output, "lucal -u") {
+		t.Fatalf("expected update hint without valid cache, got:\n%s", output)
+	}
+	SetNoUpdateHint(true)
+	defer SetNoUpdateHint(false)
+	if output := run(); strings.Contains(output, "lucal -u") {
+		t.Fatalf("expected --no-update-hint to hide the hint, got:\n%s", output)
+	}
+	if hint := UpdateHint(false); hint != "" {
+		t.Fatalf("UpdateHint should be empty when disabled, got %q", hint)
+	}
+}
+
+func TestRunPlainQuiet(t *testing.T) {
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()))
+	var buf bytes.Buffer
+	err := RunPlain(PlainOptions{
+		Writer:  &buf,
+		Service: svc,
+		Request: calendar.Request{Year: 2025, Month: 10},
+		Width:   120,
+		Quiet:   true,
+	})
+	if err != nil {
+		t.Fatalf("RunPlain failed: %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "2025 年 10 月") {
+		t.Fatalf("expected the grid, got:\n%s", output)
+	}
+	for _, extra := range []string{"lucal -u", "调休日"} {
+		if strings.Contains(output, extra) {
+			t.Fatalf("expected --quiet to drop %q, got:\n%s", extra, output)
+		}
+	}
+}
+
+func TestRunPlainYearRange(t *testing.T) {
+	SetNoColor(true)
+	defer SetNoColor(false)
+	var buf bytes.Buffer
+	err := RunPlain(PlainOptions{
+		Writer:            &buf,
+		Request:           calendar.Request{Year: 2023, Month: 1, Mode: calendar.ModeYear},
+		ToYear:            2024,
+		Width:             200,
+		HolidayCacheValid: true,
+	})
+	if err != nil {
+		t.Fatalf("RunPlain failed: %v", err)
+	}
+	output := buf.String()
+	first, second := strings.Index(output, "2023 年\n"), strings.Index(output, "2024 年\n")
+	if first < 0 || second < first {
+		t.Fatalf("expected 2023 and 2024 year headers in order, got:\n%s", output)
+	}
+	if !strings.Contains(output, "2023 年 12 月") || !strings.Contains(output, "2024 年 12 月") {
+		t.Fatalf("expected both years rendered in full, got:\n%s", output)
+	}
+
+	buf.Reset()
+	err = RunPlain(PlainOptions{
+		Writer:  &buf,
+		Request: calendar.Request{Year: 2023, Month: 1, Mode: calendar.ModeYear},
+		ToYear:  2024,
+		Format:  FormatJSON,
+	})
+	if err != nil {
+		t.Fatalf("RunPlain JSON failed: %v", err)
+	}
+	var doc struct {
+		Meta struct {
+			Request struct {
+				Year   int `json:"year"`
+				ToYear int `json:"to_year"`
+			} `json:"request"`
+		} `json:"meta"`
+		Months []json.RawMessage `json:"months"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Meta.Request.Year != 2023 || doc.Meta.Request.ToYear != 2024 || len(doc.Months) != 24 {
+		t.Fatalf("unexpected range document: %+v with %d months", doc.Meta.Request, len(doc.Months))
+	}
+}
+
+func TestRunPlainFiscalYear(t *testing.T) {
+	SetNoColor(true)
+	defer SetNoColor(false)
+	var buf bytes.Buffer
+	err := RunPlain(PlainOptions{
+		Writer:            &buf,
+		Request:           calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeYear},
+		FiscalStart:       4,
+		Width:             200,
+		HolidayCacheValid: true,
+	})
+	if err != nil {
+		t.Fatalf("RunPlain failed: %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "2025 财年（2025-04 ~ 2026-03）") {
+		t.Fatalf("expected the fiscal span header, got:\n%s", output)
+	}
+	if strings.Contains(output, "2025 年 3 月") || !strings.Contains(output, "2025 年 4 月") || !strings.Contains(output, "2026 年 3 月") {
+		t.Fatalf("expected April 2025 through March 2026, got:\n%s", output)
+	}
+
+	// cal output names the span and the year of every month.
+	buf.Reset()
+	err = RunPlain(PlainOptions{
+		Writer:      &buf,
+		Request:     calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeYear},
+		Format:      FormatCal,
+		FiscalStart: 4,
+	})
+	if err != nil {
+		t.Fatalf("RunPlain cal failed: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if header := strings.TrimSpace(lines[0]); header != "2025-2026" {
+		t.Fatalf("expected the fiscal span as the cal header, got %q", header)
+	}
+	for _, title := range []string{"April 2025", "December 2025", "January 2026", "March 2026"} {
+		if !strings.Contains(buf.String(), title) {
+			t.Fatalf("expected %q among the cal month titles, got:\n%s", title, buf.String())
+		}
+	}
+
+	// JSON records the fiscal start, so fiscal documents stand apart.
+	buf.Reset()
+	err = RunPlain(PlainOptions{
+		Writer:      &buf,
+		Request:     calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeYear},
+		Format:      FormatJSON,
+		FiscalStart: 4,
+	})
+	if err != nil {
+		t.Fatalf("RunPlain json failed: %v", err)
+	}
+	var doc struct {
+		Meta struct {
+			Request struct {
+				FiscalStart int `json:"fiscal_start"`
+			} `json:"request"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Meta.Request.FiscalStart != 4 {
+		t.Fatalf("expected fiscal_start 4 in meta.request, got %d", doc.Meta.Request.FiscalStart)
+	}
+}
+
+func TestPrintWidthReportsToItsWriter(t *testing.T) {
+	var report bytes.Buffer
+	SetPrintWidth(&report)
+	defer SetPrintWidth(nil)
+	var out bytes.Buffer
+	err := RunPlain(PlainOptions{
+		Writer:            &out,
+		Request:           calendar.Request{Year: 2025, Month: 11},
+		Width:             120,
+		HolidayCacheValid: true,
+	})
+	if err != nil {
+		t.Fatalf("RunPlain failed: %v", err)
+	}
+	if strings.Contains(out.String(), "宽度") {
+		t.Fatalf("expected the width report kept out of the calendar output, got:\n%s", out.String())
+	}
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "宽度: 终端 120，网格 ") {
+		t.Fatalf("expected a measurement line and a ruler, got:\n%s", report.String())
+	}
+	if got := widthRuler(21); got != "----+----1----+----2-" {
+		t.Fatalf("widthRuler(21) = %q", got)
+	}
+}
+
+func TestColorLegendHiddenWithoutColor(t *testing.T) {
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	views := []calendar.MonthView{view}
+	if ColorLegend(views, 0) == "" {
+		t.Fatalf("expected a legend when colors are enabled")
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	if got := ColorLegend(views, 0); got != "" {
+		t.Fatalf("expected no legend in no-color mode, got %q", got)
+	}
+}
+
+func TestColorLegendListsColorsInUse(t *testing.T) {
+	defer func() { colors = defaultPalette() }()
+	now := time.Date(2025, 10, 15, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()), calendar.WithNow(func() time.Time { return now }))
+	october, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	want := "\n" + colors.holiday + "节假日" + colorEnd + helpSeparator + colors.workday + "调休日" + colorEnd + helpSeparator + colors.today + "今天" + colorEnd
+	if got := ColorLegend([]calendar.MonthView{october}, 0); got != want {
+		t.Fatalf("ColorLegend=%q want %q", got, want)
+	}
+
+	// No holidays and no today in July 2025; weekends sharing a theme color
+	// are joined.
+	if err := ApplyTheme(Theme{"weekend": "#94A3B8"}); err != nil {
+		t.Fatalf("ApplyTheme failed: %v", err)
+	}
+	july, err := svc.Month(2025, 7)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	if got, want := ColorLegend([]calendar.MonthView{july}, 0), "\n"+colors.weekend+"周六/周日"+colorEnd; got != want {
+		t.Fatalf("ColorLegend=%q want %q", got, want)
+	}
+	colors = defaultPalette()
+	if got := ColorLegend([]calendar.MonthView{july}, 0); got != "" {
+		t.Fatalf("expected no legend without colored days, got %q", got)
+	}
+	if got := ColorLegend([]calendar.MonthView{october}, 8); strings.Count(got, "\n") != 3 {
+		t.Fatalf("expected the legend to wrap at 8 columns, got %q", got)
+	}
+}
+
+func TestSearchHighlightDimsOtherDays(t *testing.T) {
+	defer SetSearchHighlight("")
+	now := time.Date(2025, 10, 1, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()), calendar.WithNow(func() time.Time { return now }))
+	october, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetSearchHighlight("寒露")
+	for _, day := range october.Days() {
+		color, _ := dayCategory(day)
+		if want := colors.adjacent; day.Date.Day() == 8 {
+			if color != colors.search {
+				t.Fatalf("expected 10-08 (寒露) in the search color, got %q", color)
+			}
+		} else if color != want {
+			t.Fatalf("expected %s dimmed, got %q", day.Date.Format("01-02"), color)
+		}
+	}
+	if got, want := ColorLegend([]calendar.MonthView{october}, 0), "\n"+colors.search+"搜索结果"+colorEnd; got != want {
+		t.Fatalf("ColorLegend=%q want %q", got, want)
+	}
+	SetSearchHighlight(" ")
+	if color, _ := dayCategory(october.Days()[0]); color != colors.holiday {
+		t.Fatalf("expected a blank query to restore the holiday color, got %q", color)
+	}
+}
+
+func TestApplyThemeWeekendColors(t *testing.T) {
+	defer func() { colors = defaultPalette() }()
+	if err := ApplyTheme(Theme{"weekend": "#94A3B8", "sunday": "#EF4444"}); err != nil {
+		t.Fatalf("ApplyTheme failed: %v", err)
+	}
+	if colors.saturday != "\x1b[38;2;148;163;184m" || colors.sunday != "\x1b[38;2;239;68;68m" {
+		t.Fatalf("unexpected weekend colors %q %q", colors.saturday, colors.sunday)
+	}
+
+	data := map[string]map[string]*holidays.HolidayEntry{
+		"2025": {"10-05": {Holiday: true, Name: "国庆节"}},
+	}
+	svc := calendar.NewService(calendar.WithHolidays(data))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	// October 4 2025 is a Saturday, October 12 a Sunday and the 5th a
+	// Sunday holiday, which keeps the holiday color.
+	for _, want := range []string{colors.saturday + "4" + colorEnd, colors.sunday + "12" + colorEnd, colors.holiday + "5" + colorEnd} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%q", want, output)
+		}
+	}
+}
+
+func TestWorkdayAdjustmentSummaryAndJSON(t *testing.T) {
+	before := false
+	data := map[string]map[string]*holidays.HolidayEntry{
+		"2025": {
+			"09-28": {Holiday: false, Name: "国庆节前补班", Target: "国庆节", After: &before},
+			"09-20": {Holiday: false, Name: "补班"},
+		},
+	}
+	svc := calendar.NewService(calendar.WithHolidays(data))
+	view, err := svc.Month(2025, 9)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	if got, want := WorkdaySummary([]calendar.MonthView{view}), "调休：09-28 为国庆节调休，节前上班"; got != want {
+		t.Fatalf("WorkdaySummary=%q want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, []calendar.MonthView{view}, JSONOptions{}); err != nil {
+		t.Fatalf("RenderJSON failed: %v", err)
+	}
+	var out jsonOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	got := out.Months[0].Days[27].Holiday
+	if got == nil || got.Target != "国庆节" || got.After == nil || *got.After || got.Adjustment != "为国庆节调休，节前上班" {
+		t.Fatalf("unexpected holiday for 2025-09-28: %+v", got)
+	}
+}
+
+func TestHolidaysOnlyDimsWorkingDays(t *testing.T) {
+	now := time.Date(2025, 10, 15, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()), calendar.WithNow(func() time.Time { return now }))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetHolidaysOnly(true)
+	defer SetHolidaysOnly(false)
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	// October 14 2025 is an ordinary Tuesday and the 15th is today.
+	for _, want := range []string{
+		colors.adjacent + "14" + colorEnd,
+		colors.holiday + "1" + colorEnd,
+		colors.workday + "11" + colorEnd,
+		colors.today + "15" + colorEnd,
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%q", want, output)
+		}
+	}
+}
+
+func TestMarkedDaysColoredAndSummarized(t *testing.T) {
+	marks := []time.Time{
+		time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2025, 10, 20, 0, 0, 0, 0, time.Local),
+	}
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()), calendar.WithMarks(marks...))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	// A mark wins over the National Day holiday color.
+	for _, want := range []string{colors.marked + "1" + colorEnd, colors.marked + "20" + colorEnd} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%q", want, output)
+		}
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	if got, want := MarksSummary([]calendar.MonthView{view}), "标记：10-01 周三（国庆节）  10-20 周一"; got != want {
+		t.Fatalf("MarksSummary=%q want %q", got, want)
+	}
+}
+
+func TestMonthHolidaySummary(t *testing.T) {
+	data := holidaystest.Data()
+	data["2025"]["10-10"] = &holidays.HolidayEntry{Holiday: true, Name: "调休假"}
+	svc := calendar.NewService(calendar.WithHolidays(data))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	want := "节假日：国庆节 10-01~10-07（连休 7 天）、调休假 10-10；调休：10-11"
+	if got := MonthHolidaySummary(view); got != want {
+		t.Fatalf("MonthHolidaySummary=%q want %q", got, want)
+	}
+
+	SetNoColor(true)
+	defer SetNoColor(false)
+	SetHolidaySummary(true)
+	defer SetHolidaySummary(false)
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	if last := blocks[0].Lines[len(blocks[0].Lines)-1]; last != want {
+		t.Fatalf("expected the summary as the last block line, got %q", last)
+	}
+
+	november, err := svc.Month(2025, 11)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	if got := MonthHolidaySummary(november); got != "" {
+		t.Fatalf("expected no summary for a month without holidays, got %q", got)
+	}
+
+	// A Rest covering the weekend after a listed holiday is the break
+	// length; one running into a workday is not, and the days are counted.
+	for _, tt := range []struct {
+		rest int
+		want string
+	}{
+		{3, "节假日：清明节 04-04~04-06（连休 3 天）"},
+		{15, "节假日：清明节 04-04"},
+	} {
+		rest := tt.rest
+		data["2025"]["04-04"] = &holidays.HolidayEntry{Holiday: true, Name: "清明节", Wage: 3, Date: "2025-04-04", Rest: &rest}
+		april, err := calendar.NewService(calendar.WithHolidays(data)).Month(2025, 4)
+		if err != nil {
+			t.Fatalf("Month failed: %v", err)
+		}
+		if got := MonthHolidaySummary(april); got != tt.want {
+			t.Fatalf("rest %d: MonthHolidaySummary=%q want %q", rest, got, tt.want)
+		}
+	}
+}
+
+func TestMonthHolidaySummaryBreaks(t *testing.T) {
+	data := holidaystest.Data()
+	add := func(start time.Time, names ...string) {
+		for i, name := range names {
+			day := start.AddDate(0, 0, i)
+			data["2025"][day.Format("01-02")] = &holidays.HolidayEntry{Holiday: true, Name: name, Wage: 2, Date: day.Format("2006-01-02")}
+		}
+	}
+	// As in holidays.json: 中秋节 falls inside the 2025 National Day break, and
+	// every Spring Festival day has its own name.
+	add(time.Date(2025, 10, 6, 0, 0, 0, 0, time.Local), "中秋节", "国庆节", "国庆节")
+	add(time.Date(2025, 1, 28, 0, 0, 0, 0, time.Local), "除夕", "初一", "初二", "初三", "初四", "初五", "初六", "初七")
+	svc := calendar.NewService(calendar.WithHolidays(data))
+
+	for _, tt := range []struct {
+		month int
+		want  string
+	}{
+		{10, "节假日：国庆节/中秋节 10-01~10-08（连休 8 天）；调休：10-11"},
+		{1, "节假日：元旦 01-01、春节 01-28~02-04（连休 8 天）"},
+		{2, "节假日：春节 02-01~02-04"},
+	} {
+		view, err := svc.Month(2025, tt.month)
+		if err != nil {
+			t.Fatalf("Month failed: %v", err)
+		}
+		if got := MonthHolidaySummary(view); got != tt.want {
+			t.Fatalf("month %d: MonthHolidaySummary=%q want %q", tt.month, got, tt.want)
+		}
+	}
+}
+
+func TestHelpLineFitsWidth(t *testing.T) {
+	SetNoColor(true)
+	defer SetNoColor(false)
+	full := HelpLine(6, 0)
+	if !strings.Contains(full, "^F/^B 前进/后退 6 个月") || strings.Contains(full, "\n") {
+		t.Fatalf("expected the full single-line help, got %q", full)
+	}
+	if got := HelpLine(6, textwidth.StringWidth(full)); got != full {
+		t.Fatalf("expected the full help when it fits exactly, got %q", got)
+	}
+
+	short := HelpLine(6, 80)
+	if !strings.HasPrefix(short, "导航 j/k/J/K") || strings.Contains(short, "\n") {
+		t.Fatalf("expected the abbreviated help at 80 columns, got %q", short)
+	}
+	narrow := HelpLine(6, 20)
+	for _, line := range strings.Split(narrow, "\n") {
+		if textwidth.StringWidth(line) > 20 {
+			t.Fatalf("line %q is wider than 20 columns in %q", line, narrow)
+		}
+	}
+	if !strings.Contains(narrow, "\n") {
+		t.Fatalf("expected the abbreviated help to wrap at 20 columns, got %q", narrow)
+	}
+}
+
+func TestDenseDropsSpacerRows(t *testing.T) {
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	normal, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	SetDense(true)
+	defer SetDense(false)
+	dense, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	// October 2025 spans five weeks: one spacer under the header and four
+	// between weeks.
+	if got, want := dense[0].Height, normal[0].Height-5; got != want {
+		t.Fatalf("dense height=%d want %d", got, want)
+	}
+	output := strings.Join(dense[0].Lines, "\n")
+	// The lunar row still sits right under the date row and keeps its color.
+	for _, want := range []string{colors.holiday + "1" + colorEnd, colors.holiday + "初十" + colorEnd} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%q", want, output)
+		}
+	}
+
+	views, err := svc.Year(2025)
+	if err != nil {
+		t.Fatalf("Year failed: %v", err)
+	}
+	blocks, err := BuildBlocks(views)
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	for _, block := range blocks[1:] {
+		if block.Height != blocks[0].Height {
+			t.Fatalf("expected equal dense block heights in a year grid, got %d and %d", blocks[0].Height, block.Height)
+		}
+	}
+}
+
+func TestSixWeeksPadsGrids(t *testing.T) {
+	svc := calendar.NewService()
+	views, err := svc.Year(2025)
+	if err != nil {
+		t.Fatalf("Year failed: %v", err)
+	}
+	blocks, err := BuildBlocks(views)
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	for _, block := range blocks[1:] {
+		if block.Height != blocks[0].Height {
+			t.Fatalf("expected equal block heights in a year grid, got %d and %d", blocks[0].Height, block.Height)
+		}
+	}
+
+	// November 2025 spans six weeks and February 2026 only four.
+	february, err := svc.Month(2026, 2)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	single, err := BuildBlocks([]calendar.MonthView{february})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	if single[0].Height >= blocks[10].Height {
+		t.Fatalf("expected a single month to keep its natural height, got %d", single[0].Height)
+	}
+
+	defer SetSixWeeks(SixWeeksAuto)
+	if err := SetSixWeeks(SixWeeksOn); err != nil {
+		t.Fatalf("SetSixWeeks failed: %v", err)
+	}
+	if padded, _ := BuildBlocks([]calendar.MonthView{february}); padded[0].Height != blocks[10].Height {
+		t.Fatalf("expected a padded single month to match a six-week month, got %d want %d", padded[0].Height, blocks[10].Height)
+	}
+	if err := SetSixWeeks(SixWeeksOff); err != nil {
+		t.Fatalf("SetSixWeeks failed: %v", err)
+	}
+	if unpadded, _ := BuildBlocks(views); unpadded[0].Height == unpadded[10].Height {
+		t.Fatal("expected natural heights with six-weeks off")
+	}
+	if err := SetSixWeeks("always"); err == nil {
+		t.Fatal("expected an error for an unknown mode")
+	}
+}
+
+func TestApplyThemeRejectsBadInput(t *testing.T) {
+	defer func() { colors = defaultPalette() }()
+	for _, theme := range []Theme{{"weekend": "blue"}, {"weekday": "#000000"}} {
+		if err := ApplyTheme(theme); err == nil {
+			t.Fatalf("expected error for %v", theme)
+		}
+	}
+	if colors != defaultPalette() {
+		t.Fatalf("expected palette untouched after a failed ApplyTheme")
+	}
+}
+
+func TestSetColorsOverridesTheme(t *testing.T) {
+	defer func() { colors = defaultPalette() }()
+	if err := ApplyTheme(Theme{"holiday": "#000001", "weekend": "#000002"}); err != nil {
+		t.Fatalf("ApplyTheme failed: %v", err)
+	}
+	if err := SetColors(Theme{"holiday": "#0000ff"}); err != nil {
+		t.Fatalf("SetColors failed: %v", err)
+	}
+	if colors.holiday != "\x1b[38;2;0;0;255m" {
+		t.Fatalf("expected the override to replace the theme holiday color, got %q", colors.holiday)
+	}
+	if colors.sunday != "\x1b[38;2;0;0;2m" || colors.today != defaultPalette().today {
+		t.Fatalf("expected other colors kept from the theme and defaults, got %+v", colors)
+	}
+
+	before := colors
+	if err := SetColors(Theme{"today": "#12345"}); err == nil {
+		t.Fatal("expected an error for an invalid hex color")
+	}
+	if colors != before {
+		t.Fatal("expected palette untouched after a failed SetColors")
+	}
+}
+
+func TestDayAlign(t *testing.T) {
+	svc := calendar.NewService()
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	SetNoColor(true)
+	defer SetNoColor(false)
+	defer SetDayAlign(DayAlignLeft)
+
+	render := func() string {
+		blocks, err := BuildBlocks([]calendar.MonthView{view})
+		if err != nil {
+			t.Fatalf("BuildBlocks failed: %v", err)
+		}
+		return Layout(blocks, 120)
+	}
+	left := render()
+	if err := SetDayAlign(DayAlignCenter); err != nil {
+		t.Fatalf("SetDayAlign failed: %v", err)
+	}
+	centered := render()
+
+	// Lunar labels are four columns wide: "12" moves one column right to
+	// sit centered over 廿一, while " 1" already is as centered as it gets.
+	if !strings.Contains(left, "\n 12      13") || !strings.Contains(left, "  1       2") {
+		t.Fatalf("expected left-aligned day numbers:\n%s", left)
+	}
+	if !strings.Contains(centered, "\n  12      13") || !strings.Contains(centered, "  1       2") {
+		t.Fatalf("expected centered day numbers:\n%s", centered)
+	}
+	if textwidth.StringWidth(left) != textwidth.StringWidth(centered) {
+		t.Fatalf("centering changed the grid width:\n%s\n%s", left, centered)
+	}
+	if err := SetDayAlign("right"); err == nil {
+		t.Fatal("expected an error for an unknown alignment")
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	now := time.Date(2025, 10, 15, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()), calendar.WithNow(func() time.Time { return now }))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := RenderSVG(&buf, []calendar.MonthView{view}, SVGOptions{}); err != nil {
+		t.Fatalf("RenderSVG failed: %v", err)
+	}
+	output := buf.String()
+
+	decoder := xml.NewDecoder(strings.NewReader(output))
+	var texts []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, output)
+		}
+		if data, ok := token.(xml.CharData); ok && strings.TrimSpace(string(data)) != "" {
+			texts = append(texts, strings.TrimSpace(string(data)))
+		}
+	}
+	joined := strings.Join(texts, "|")
+	for _, want := range []string{"2025 年 10 月", "日|一|二", "|1|初十|", "|23|霜降|"} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected %q among the SVG texts: %s", want, joined)
+		}
+	}
+	// The 24-bit palette colors carry over: blue holidays, green today.
+	for _, want := range []string{`fill="#3B82F6"`, `stroke="#34D399"`} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %s in the SVG:\n%s", want, output)
+		}
+	}
+
+	year, err := svc.Year(2025)
+	if err != nil {
+		t.Fatalf("Year failed: %v", err)
+	}
+	if err := RenderSVG(io.Discard, year, SVGOptions{}); !errors.Is(err, ErrSVGMultipleMonths) {
+		t.Fatalf("expected ErrSVGMultipleMonths for a year, got %v", err)
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	now := time.Date(2025, 10, 15, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithHolidays(holidaystest.Data()), calendar.WithNow(func() time.Time { return now }))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	decode := func(opts PNGOptions) image.Image {
+		var buf bytes.Buffer
+		if err := RenderPNG(&buf, []calendar.MonthView{view}, opts); err != nil {
+			t.Fatalf("RenderPNG failed: %v", err)
+		}
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatalf("invalid PNG: %v", err)
+		}
+		return img
+	}
+	native, scaled := decode(PNGOptions{Scale: 1}), decode(PNGOptions{})
+	if got, want := scaled.Bounds().Size(), native.Bounds().Size().Mul(DefaultPNGScale); got != want {
+		t.Fatalf("default size %v want %v", got, want)
+	}
+	// Columns follow textwidth: seven cells of the widest label plus padding.
+	if got, want := native.Bounds().Dx(), 7*(determineColumnWidth(view)+cellPadding*2)*pngColumn+2*pngLineHeight; got != want {
+		t.Fatalf("width %d want %d", got, want)
+	}
+	// The palette colors carry over: blue holidays, green today.
+	for _, want := range []string{"#3B82F6", "#34D399"} {
+		found := false
+		bounds := native.Bounds()
+		for y := bounds.Min.Y; y < bounds.Max.Y && !found; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X && !found; x++ {
+				found = color.RGBAModel.Convert(native.At(x, y)) == hexColor(want)
+			}
+		}
+		if !found {
+			t.Fatalf("expected pixels of %s in the PNG", want)
+		}
+	}
+
+	year, err := svc.Year(2025)
+	if err != nil {
+		t.Fatalf("Year failed: %v", err)
+	}
+	if err := RenderPNG(io.Discard, year, PNGOptions{}); !errors.Is(err, ErrPNGMultipleMonths) {
+		t.Fatalf("expected ErrPNGMultipleMonths for a year, got %v", err)
+	}
+}
+
+func TestHolidayRules(t *testing.T) {
+	defer SetHolidayRules(nil)
+	statutory := "\x1b[38;2;239;68;68m"
+	springFestival := "\x1b[38;2;245;158;11m"
+	rules := []HolidayRule{
+		{Name: "^春节$", Color: "#F59E0B"},
+		{MinWage: 3, Color: "#EF4444", Label: "法定节假日"},
+	}
+	if err := SetHolidayRules(rules); err != nil {
+		t.Fatalf("SetHolidayRules failed: %v", err)
+	}
+	data := map[string]map[string]*holidays.HolidayEntry{
+		"2025": {
+			"10-01": {Holiday: true, Name: "国庆节", Wage: 3},
+			"10-04": {Holiday: true, Name: "国庆节", Wage: 2},
+			"10-11": {Holiday: false, Name: "国庆节", Wage: 1},
+			"10-20": {Holiday: true, Name: "春节", Wage: 3},
+		},
+	}
+	svc := calendar.NewService(calendar.WithHolidays(data))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		t.Fatalf("Month failed: %v", err)
+	}
+	blocks, err := BuildBlocks([]calendar.MonthView{view})
+	if err != nil {
+		t.Fatalf("BuildBlocks failed: %v", err)
+	}
+	output := Layout(blocks, 120)
+	for _, want := range []string{
+		statutory + "1" + colorEnd,
+		colors.holiday + "4" + colorEnd, // wage 2 matches no rule
+		colors.workday + "11" + colorEnd,
+		springFestival + "20" + colorEnd, // the first matching rule wins
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%q", want, output)
+		}
+	}
+	if legend := ColorLegend([]calendar.MonthView{view}, 0); !strings.Contains(legend, statutory+"法定节假日"+colorEnd) {
+		t.Fatalf("expected the labelled rule in the legend, got %q", legend)
+	}
+
+	for _, bad := range [][]HolidayRule{
+		{{Color: "#EF4444"}},
+		{{MinWage: 3, Color: "red"}},
+		{{Name: "(", Color: "#EF4444"}},
+	} {
+		if err := SetHolidayRules(bad); err == nil {
+			t.Fatalf("expected an error for %+v", bad)
+		}
+	}
+	if len(holidayCategories) != 2 {
+		t.Fatalf("expected a failed SetHolidayRules to keep the rules, got %d", len(holidayCategories))
+	}
+}
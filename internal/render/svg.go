@@ -0,0 +1,138 @@
+package render
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/textwidth"
+)
+
+// FormatSVG selects RenderSVG for a single requested month.
+const FormatSVG = "svg"
+
+// DefaultSVGFontSize is the font size RenderSVG uses unless told otherwise.
+const DefaultSVGFontSize = 16
+
+// SVG colors for text without a palette color and for the background.
+const (
+	svgForeground = "#1F2937"
+	svgBackground = "#FFFFFF"
+	svgHeader     = "#6366F1"
+)
+
+// ErrSVGMultipleMonths is returned when RenderSVG is asked for anything but
+// one month; year grids are not supported yet.
+var ErrSVGMultipleMonths = errors.New("svg 格式目前只支持单个月份")
+
+// SVGOptions controls RenderSVG.
+type SVGOptions struct {
+	FontSize int // pixels; 0 means DefaultSVGFontSize
+}
+
+// RenderSVG draws views, which must hold exactly one month, as a standalone
+// SVG image laid out like the text grid: a title, the weekday header and
+// every day's cell lines per SetLunarPosition. Text is positioned on a grid
+// of monospace columns 0.6 font sizes wide, and each run is stretched to
+// its textwidth width so CJK labels take two columns whatever the viewer's
+// font. Days are colored from the current palette, and today is outlined.
+// SetNoColor draws everything in one color.
+func RenderSVG(w io.Writer, views []calendar.MonthView, opts SVGOptions) error {
+	if len(views) != 1 {
+		return ErrSVGMultipleMonths
+	}
+	view := views[0]
+	fontSize := float64(opts.FontSize)
+	if fontSize <= 0 {
+		fontSize = DefaultSVGFontSize
+	}
+	column := fontSize * 0.6
+	lineHeight := fontSize * 1.5
+	cellWidth := float64(determineColumnWidth(view)+cellPadding*2) * column
+	margin := lineHeight
+
+	var body strings.Builder
+	text := func(x, y float64, s, color string, attrs string) {
+		if strings.TrimSpace(s) == "" {
+			return
+		}
+		fmt.Fprintf(&body, `<text x="%s" y="%s" fill="%s" textLength="%s" lengthAdjust="spacingAndGlyphs"%s>`,
+			svgNumber(x), svgNumber(y), color, svgNumber(float64(textwidth.StringWidth(s))*column), attrs)
+		xml.EscapeText(&body, []byte(s))
+		body.WriteString("</text>\n")
+	}
+
+	width := 7*cellWidth + 2*margin
+	y := margin + fontSize
+	title := view.Title
+	text(margin+(7*cellWidth-float64(textwidth.StringWidth(title))*column)/2, y, title, svgForeground, ` font-weight="bold"`)
+
+	y += lineHeight * 1.5
+	headerColor := svgHeader
+	if noColorMode {
+		headerColor = svgForeground
+	}
+	for i, name := range rotateWeekdays(weekdays, view.WeekStart) {
+		text(margin+float64(i)*cellWidth+cellPadding*column, y, name, headerColor, ` font-weight="bold"`)
+	}
+
+	y += lineHeight
+	for _, week := range view.Weeks {
+		lineCount := 0
+		for i, day := range week {
+			lines, _ := dayCellLines(day)
+			lineCount = max(lineCount, len(lines))
+			color := svgForeground
+			if !noColorMode {
+				if hex := sequenceHex(dayColor(day)); hex != "" {
+					color = hex
+				}
+			}
+			x := margin + float64(i)*cellWidth
+			if day.InMonth && day.IsToday {
+				fmt.Fprintf(&body, `<rect x="%s" y="%s" width="%s" height="%s" rx="%s" fill="none" stroke="%s"/>`+"\n",
+					svgNumber(x+column/2), svgNumber(y), svgNumber(cellWidth-column), svgNumber(float64(len(lines))*lineHeight+fontSize/2),
+					svgNumber(fontSize/4), color)
+			}
+			for r, line := range lines {
+				text(x+cellPadding*column, y+fontSize+float64(r)*lineHeight, line, color, "")
+			}
+		}
+		y += float64(lineCount)*lineHeight + lineHeight/2
+	}
+	height := y + margin
+
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="monospace" font-size="%s" xml:space="preserve">
+<rect width="100%%" height="100%%" fill="%s"/>
+%s</svg>
+`, svgNumber(width), svgNumber(height), svgNumber(width), svgNumber(height), svgNumber(fontSize), svgBackground, body.String())
+	return err
+}
+
+// sequenceHex converts a palette sequence made by foregroundSequence back to
+// "#RRGGBB"; anything else, such as "", yields "".
+func sequenceHex(seq string) string {
+	fields := strings.Split(strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b["), "m"), ";")
+	if len(fields) < 5 || fields[len(fields)-5] != "38" || fields[len(fields)-4] != "2" {
+		return ""
+	}
+	hex := "#"
+	for _, field := range fields[len(fields)-3:] {
+		v, err := strconv.Atoi(field)
+		if err != nil || v < 0 || v > 255 {
+			return ""
+		}
+		hex += fmt.Sprintf("%02X", v)
+	}
+	return hex
+}
+
+// svgNumber formats a coordinate with at most two decimals.
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
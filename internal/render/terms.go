@@ -0,0 +1,53 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/textwidth"
+)
+
+// nextTermMarker points at the first solar term on or after today.
+const nextTermMarker = "▶"
+
+// SolarTermsPanel lists the solar terms of year one per line, e.g.
+// "  冬至  12-21 周日", under a "2025 年二十四节气" title. The first term on
+// or after today is marked with nextTermMarker and drawn in the today color.
+// Like month grids, the list is boxed unless colors or borders are off.
+func SolarTermsPanel(year int, terms []calendar.SolarTerm, today time.Time) string {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	lines := make([]string, 0, len(terms))
+	marked := false
+	for _, term := range terms {
+		marker := " "
+		if !marked && !term.Date.Before(today) {
+			marker = nextTermMarker
+		}
+		line := fmt.Sprintf("%s %s  %s 周%s", marker, textwidth.PadRight(term.Name, 4),
+			term.Date.Format("01-02"), weekdays[term.Date.Weekday()])
+		if marker == nextTermMarker {
+			marked = true
+			if !noColorMode && colors.today != "" {
+				line = colors.today + line + colorEnd
+			}
+		}
+		lines = append(lines, line)
+	}
+	body := strings.Join(lines, "\n")
+	width := 0
+	for _, line := range lines {
+		width = max(width, textwidth.StringWidth(line))
+	}
+	if !noColorMode && !noBorderMode {
+		body = tableWrapperStyle.Render(body)
+		width += tableWrapperStyle.GetHorizontalFrameSize()
+	}
+
+	title := fmt.Sprintf("%d 年二十四节气", year)
+	if !noColorMode {
+		title = titleStyle.Render(title)
+	}
+	return strings.TrimRight(textwidth.Center(title, width), " ") + "\n\n" + body
+}
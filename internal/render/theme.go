@@ -0,0 +1,138 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// palette holds the escape sequences cells are colored with. An empty entry
+// leaves that category uncolored.
+type palette struct {
+	holiday  string
+	workday  string
+	today    string
+	adjacent string
+	saturday string
+	sunday   string
+	weekend  string // weekend days other than Saturday and Sunday
+	marked   string
+	search   string // days matching SetSearchHighlight
+}
+
+func defaultPalette() palette {
+	return palette{
+		holiday:  "\x1b[38;2;59;130;246m",  // Blue for holidays
+		workday:  "\x1b[38;2;249;115;22m",  // Orange for workdays (调休)
+		today:    "\x1b[38;2;52;211;153m",  // Green for today
+		adjacent: "\x1b[38;2;107;114;128m", // Gray for adjacent-month days, matches dimCellStyle
+		marked:   "\x1b[38;2;217;70;239m",  // Magenta for --mark dates
+		search:   "\x1b[38;2;234;179;8m",   // Yellow for search matches
+		// Weekends stay uncolored unless a theme sets them.
+	}
+}
+
+var colors = defaultPalette()
+
+// Theme maps color keys to "#RRGGBB" values. Recognised keys are holiday,
+// workday, today, adjacent, marked, weekend, saturday and sunday; weekend sets every
+// weekend day and saturday/sunday override it individually.
+type Theme map[string]string
+
+// LoadTheme reads a Theme from a JSON object such as
+//
+//	{"weekend": "#94A3B8", "sunday": "#EF4444"}
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme file: %w", err)
+	}
+	var theme Theme
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return nil, fmt.Errorf("failed to parse theme JSON: %w", err)
+	}
+	return theme, nil
+}
+
+// ApplyTheme validates theme and replaces the matching palette entries,
+// leaving the others at their defaults. Nothing changes when it fails.
+func ApplyTheme(theme Theme) error {
+	next := defaultPalette()
+	if err := next.apply(theme); err != nil {
+		return err
+	}
+	colors = next
+	return nil
+}
+
+// SetColors validates overrides (keyed like Theme) and replaces the matching
+// entries of the current palette, keeping whatever the defaults or an
+// earlier ApplyTheme set for the rest. Nothing changes when it fails.
+func SetColors(overrides Theme) error {
+	next := colors
+	if err := next.apply(overrides); err != nil {
+		return err
+	}
+	colors = next
+	return nil
+}
+
+// apply replaces the entries of p named by theme.
+func (p *palette) apply(theme Theme) error {
+	keys := make([]string, 0, len(theme))
+	for key := range theme {
+		keys = append(keys, key)
+	}
+	// weekend is applied first so saturday/sunday can override it.
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "weekend") != (keys[j] == "weekend") {
+			return keys[i] == "weekend"
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		seq, err := foregroundSequence(theme[key])
+		if err != nil {
+			return fmt.Errorf("theme key %q: %w", key, err)
+		}
+		switch key {
+		case "holiday":
+			p.holiday = seq
+		case "workday":
+			p.workday = seq
+		case "today":
+			p.today = seq
+		case "adjacent":
+			p.adjacent = seq
+		case "marked":
+			p.marked = seq
+		case "search":
+			p.search = seq
+		case "weekend":
+			p.saturday, p.sunday, p.weekend = seq, seq, seq
+		case "saturday":
+			p.saturday = seq
+		case "sunday":
+			p.sunday = seq
+		default:
+			return fmt.Errorf("unknown theme key %q", key)
+		}
+	}
+	return nil
+}
+
+// foregroundSequence converts "#RRGGBB" to a 24-bit foreground sequence.
+func foregroundSequence(hex string) (string, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(value) != 6 {
+		return "", fmt.Errorf("invalid color %q, expected #RRGGBB", hex)
+	}
+	rgb, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid color %q, expected #RRGGBB", hex)
+	}
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", rgb>>16, (rgb>>8)&0xff, rgb&0xff), nil
+}
@@ -0,0 +1,5 @@
+package textwidth
+
+// GBKWidth exposes the encoder-backed width so benchmarks can compare it
+// against the ASCII fast path.
+var GBKWidth = gbkWidth
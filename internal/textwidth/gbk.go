@@ -7,13 +7,35 @@ import (
 
 	"golang.org/x/text/encoding/simplifiedchinese"
 	"golang.org/x/text/transform"
+	"golang.org/x/text/width"
 )
 
-var ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+// ambiguousWidth is the column count of East Asian Ambiguous runes such as
+// box drawing and some punctuation; terminals render them as 1 or 2 columns
+// depending on their settings.
+var ambiguousWidth = 1
+
+// SetAmbiguousWidth sets how many columns an East Asian Ambiguous rune
+// occupies. Only 1 and 2 are meaningful; other values are ignored.
+func SetAmbiguousWidth(n int) {
+	if n == 1 || n == 2 {
+		ambiguousWidth = n
+	}
+}
+
+// AmbiguousWidth reports the current width of East Asian Ambiguous runes.
+func AmbiguousWidth() int {
+	return ambiguousWidth
+}
+
+// ansiRegexp matches SGR color sequences and OSC 8 hyperlink markers, which
+// may end with either ST (ESC \) or BEL.
+var ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;]*m|\x1b\]8;[^\x1b\x07]*(?:\x1b\\|\x07)`)
 
 // StringWidth returns the maximum visual width (in monospace columns) of the
 // provided string. It treats a single Chinese character as occupying two
-// columns by encoding the string as GBK per the project requirements.
+// columns by encoding the string as GBK per the project requirements, unless
+// SetMode picked another strategy.
 func StringWidth(s string) int {
 	if s == "" {
 		return 0
@@ -37,11 +59,54 @@ func PadRight(s string, width int) string {
 	return s + strings.Repeat(" ", diff)
 }
 
+// PadLeft prepends ASCII spaces until the rendered width matches target.
+func PadLeft(s string, width int) string {
+	diff := width - StringWidth(s)
+	if diff <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", diff) + s
+}
+
+// Center pads both sides with ASCII spaces so s sits in the middle of width
+// columns. When the padding can't be split evenly the extra space goes to the
+// right.
+func Center(s string, width int) string {
+	diff := width - StringWidth(s)
+	if diff <= 0 {
+		return s
+	}
+	left := diff / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", diff-left)
+}
+
 func lineWidth(s string) int {
 	if s == "" {
 		return 0
 	}
-	clean := stripANSI(s)
+	// Pure ASCII encodes to itself in GBK, so skip the encoder entirely.
+	if isASCII(s) {
+		if strings.IndexByte(s, '\x1b') < 0 {
+			return len(s)
+		}
+		return len(stripANSI(s))
+	}
+	return measure(stripANSI(s))
+}
+
+// ambiguousAdjustment corrects the GBK width of the ambiguous runes in clean,
+// which GBK counts by their encoded length, to ambiguousWidth columns each.
+func ambiguousAdjustment(clean string) int {
+	adjust := 0
+	for _, r := range clean {
+		if r > unicode.MaxASCII && width.LookupRune(r).Kind() == width.EastAsianAmbiguous {
+			adjust += ambiguousWidth - gbkWidth(string(r))
+		}
+	}
+	return adjust
+}
+
+func gbkWidth(clean string) int {
 	encoder := simplifiedchinese.GBK.NewEncoder()
 	encoded, _, err := transform.String(encoder, clean)
 	if err != nil {
@@ -50,6 +115,15 @@ func lineWidth(s string) int {
 	return len(encoded)
 }
 
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
 func stripANSI(s string) string {
 	return ansiRegexp.ReplaceAllString(s, "")
 }
@@ -68,4 +142,3 @@ func fallbackWidth(s string) int {
 	}
 	return width
 }
-
@@ -16,6 +16,10 @@ func TestStringWidthMixedScripts(t *testing.T) {
 		{"chinese", "中文", 4},
 		{"mixed", "A中", 3},
 		{"multiline", "ab\n中文", 4},
+		{"ascii with ansi", "\x1b[38;2;59;130;246m11\x1b[0m", 2},
+		{"chinese with ansi", "\x1b[1m廿二\x1b[0m", 4},
+		{"hyperlink", "\x1b]8;;https://example.com/2025-11-18\x1b\\18\x1b]8;;\x1b\\", 2},
+		{"colored hyperlink with bel", "\x1b[32m\x1b]8;;https://example.com\a十八\x1b]8;;\a\x1b[0m", 4},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -26,6 +30,64 @@ func TestStringWidthMixedScripts(t *testing.T) {
 	}
 }
 
+func TestAmbiguousWidth(t *testing.T) {
+	defer textwidth.SetAmbiguousWidth(textwidth.AmbiguousWidth())
+	// ─ and · are East Asian Ambiguous; 中 is Wide and must not change.
+	tests := []struct {
+		ambiguous int
+		in        string
+		want      int
+	}{
+		{1, "─", 1},
+		{1, "a·b", 3},
+		{1, "中─", 3},
+		{2, "─", 2},
+		{2, "a·b", 4},
+		{2, "\x1b[1m中─\x1b[0m", 4},
+	}
+	for _, tt := range tests {
+		textwidth.SetAmbiguousWidth(tt.ambiguous)
+		if got := textwidth.StringWidth(tt.in); got != tt.want {
+			t.Fatalf("ambiguous=%d StringWidth(%q)=%d want %d", tt.ambiguous, tt.in, got, tt.want)
+		}
+	}
+	textwidth.SetAmbiguousWidth(3)
+	if got := textwidth.AmbiguousWidth(); got != 2 {
+		t.Fatalf("SetAmbiguousWidth(3) should be ignored, got %d", got)
+	}
+}
+
+func TestWidthModes(t *testing.T) {
+	defer textwidth.SetMode(textwidth.Mode())
+	// ｱ is halfwidth katakana, absent from GBK; ─ is East Asian Ambiguous.
+	tests := []struct {
+		mode string
+		in   string
+		want int
+	}{
+		{textwidth.ModeGBK, "ｱ", 2},
+		{textwidth.ModeUnicode, "ｱ", 1},
+		{textwidth.ModeAmbiguous1, "ｱ", 1},
+		{textwidth.ModeGBK, "中─", 3},
+		{textwidth.ModeUnicode, "中─", 3},
+		{textwidth.ModeAmbiguous1, "中─", 3},
+		{textwidth.ModeAmbiguous2, "中─", 4},
+		{textwidth.ModeUnicode, "e\u0301", 1},
+		{textwidth.ModeAmbiguous2, "\x1b[1m初一\x1b[0m", 4},
+	}
+	for _, tt := range tests {
+		if err := textwidth.SetMode(tt.mode); err != nil {
+			t.Fatalf("SetMode(%s) failed: %v", tt.mode, err)
+		}
+		if got := textwidth.StringWidth(tt.in); got != tt.want {
+			t.Fatalf("mode=%s StringWidth(%q)=%d want %d", tt.mode, tt.in, got, tt.want)
+		}
+	}
+	if err := textwidth.SetMode("utf8"); err == nil || textwidth.Mode() != textwidth.ModeAmbiguous2 {
+		t.Fatalf("expected an unknown mode to be rejected and ignored, got %v, %s", err, textwidth.Mode())
+	}
+}
+
 func TestPadRight(t *testing.T) {
 	got := textwidth.PadRight("中", 4)
 	if textwidth.StringWidth(got) != 4 {
@@ -36,3 +98,52 @@ func TestPadRight(t *testing.T) {
 	}
 }
 
+func TestPadLeft(t *testing.T) {
+	got := textwidth.PadLeft("中", 4)
+	if got != "  中" {
+		t.Fatalf("PadLeft=%q want %q", got, "  中")
+	}
+	if colored := textwidth.PadLeft("\x1b[32m中\x1b[0m", 3); textwidth.StringWidth(colored) != 3 {
+		t.Fatalf("PadLeft should ignore ANSI codes, width=%d", textwidth.StringWidth(colored))
+	}
+}
+
+func TestCenter(t *testing.T) {
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{4, "中文"},
+		{5, "中文 "},
+		{7, " 中文  "},
+		{8, "  中文  "},
+	}
+	for _, tt := range tests {
+		if got := textwidth.Center("中文", tt.width); got != tt.want {
+			t.Fatalf("Center(中文, %d)=%q want %q", tt.width, got, tt.want)
+		}
+	}
+}
+
+// calendarCells mirrors the strings measured while rendering a month.
+var calendarCells = []string{" 1", "11*", "30", "初一", "廿二", "立冬", "闰二月", "  "}
+
+func BenchmarkStringWidth(b *testing.B) {
+	b.Run("cells", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, cell := range calendarCells {
+				textwidth.StringWidth(cell)
+			}
+		}
+	})
+	b.Run("ascii", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			textwidth.StringWidth(" 11")
+		}
+	})
+	b.Run("ascii-gbk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			textwidth.GBKWidth(" 11")
+		}
+	})
+}
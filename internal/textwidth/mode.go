@@ -0,0 +1,81 @@
+package textwidth
+
+import (
+	"fmt"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// Measurement strategies for SetMode.
+const (
+	// ModeGBK counts the bytes of the GBK encoding, so every Chinese
+	// character is two columns; Ambiguous runes follow SetAmbiguousWidth.
+	ModeGBK = "gbk"
+	// ModeUnicode uses the East Asian Width property: Wide and Fullwidth
+	// runes are two columns, combining marks none; Ambiguous runes follow
+	// SetAmbiguousWidth.
+	ModeUnicode = "unicode"
+	// ModeAmbiguous1 and ModeAmbiguous2 are ModeUnicode with Ambiguous runes
+	// fixed at one or two columns.
+	ModeAmbiguous1 = "ambiguous1"
+	ModeAmbiguous2 = "ambiguous2"
+)
+
+// mode is the strategy lineWidth measures non-ASCII text with.
+var mode = ModeGBK
+
+// SetMode selects how non-ASCII text is measured: ModeGBK (the default),
+// ModeUnicode, ModeAmbiguous1 or ModeAmbiguous2. Terminals disagree on some
+// runes, so the mode that aligns depends on the terminal.
+func SetMode(m string) error {
+	switch m {
+	case ModeGBK, ModeUnicode, ModeAmbiguous1, ModeAmbiguous2:
+		mode = m
+		return nil
+	}
+	return fmt.Errorf("不支持的宽度模式 %q，可选 gbk、unicode、ambiguous1 或 ambiguous2", m)
+}
+
+// Mode reports the current measurement strategy.
+func Mode() string {
+	return mode
+}
+
+// measure returns the width of clean, which holds no escape sequences,
+// under the current mode.
+func measure(clean string) int {
+	switch mode {
+	case ModeUnicode:
+		return unicodeWidth(clean, ambiguousWidth)
+	case ModeAmbiguous1:
+		return unicodeWidth(clean, 1)
+	case ModeAmbiguous2:
+		return unicodeWidth(clean, 2)
+	}
+	return gbkWidth(clean) + ambiguousAdjustment(clean)
+}
+
+// unicodeWidth sums the East Asian Width of the runes of clean, counting
+// Ambiguous runes as ambiguous columns.
+func unicodeWidth(clean string, ambiguous int) int {
+	total := 0
+	for _, r := range clean {
+		switch {
+		case r == '\n' || r == '\r':
+		case r <= unicode.MaxASCII:
+			total++
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		default:
+			switch width.LookupRune(r).Kind() {
+			case width.EastAsianWide, width.EastAsianFullwidth:
+				total += 2
+			case width.EastAsianAmbiguous:
+				total += ambiguous
+			default:
+				total++
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/render"
+)
+
+// fallbackWidth lays out the calendar when the width is not known yet.
+const fallbackWidth = 100
+
+// CalendarComponent is the calendar grid of the interactive UI as a widget
+// for other Bubble Tea programs: it renders a month (or a year) into a
+// string that can be placed anywhere in a lipgloss layout, and never touches
+// the terminal itself. Run hosts one full screen.
+//
+// The contract is that of a nested Bubble Tea model: the host forwards
+// messages to Update and stores the returned component, then calls View
+// with the width it has to spare. Update acts on NavigateMsg, GotoMsg and
+// TodayMsg and ignores everything else, so key bindings stay with the host.
+// Nothing is cached: every View reads the service clock, so the today
+// highlight follows midnight as soon as the host redraws.
+type CalendarComponent struct {
+	svc     *calendar.Service
+	request calendar.Request
+	// months is how many consecutive months the month view shows; 0 or 1
+	// is a single month.
+	months int
+}
+
+// NavigateMsg moves a CalendarComponent by Years and Months.
+type NavigateMsg struct {
+	Years, Months int
+}
+
+// GotoMsg makes a CalendarComponent show Request.
+type GotoMsg struct {
+	Request calendar.Request
+}
+
+// TodayMsg makes a CalendarComponent show the current month of its service
+// clock.
+type TodayMsg struct{}
+
+// NewCalendarComponent returns a component showing req with the data of svc
+// (calendar.NewService() when nil).
+func NewCalendarComponent(svc *calendar.Service, req calendar.Request) CalendarComponent {
+	if svc == nil {
+		svc = calendar.NewService()
+	}
+	return CalendarComponent{svc: svc, request: req.Normalize()}
+}
+
+// Request reports the month or year the component shows.
+func (c CalendarComponent) Request() calendar.Request {
+	return c.request
+}
+
+// Update applies a navigation message. It never returns a command.
+func (c CalendarComponent) Update(msg tea.Msg) (CalendarComponent, tea.Cmd) {
+	switch msg := msg.(type) {
+	case NavigateMsg:
+		c.request = c.request.Add(msg.Years, msg.Months)
+	case GotoMsg:
+		c.request = msg.Request.Normalize()
+	case TodayMsg:
+		c.request = todayRequest(c.svc.Now())
+	}
+	return c, nil
+}
+
+// Views returns the months View draws.
+func (c CalendarComponent) Views() ([]calendar.MonthView, error) {
+	if c.request.Mode == calendar.ModeYear {
+		if fiscalStart > 1 {
+			return c.svc.Months(c.request.Year, fiscalStart, 12)
+		}
+		return c.svc.Year(c.request.Year)
+	}
+	if c.months > 1 {
+		return c.svc.Months(c.request.Year, c.request.Month, c.months)
+	}
+	month, err := c.svc.Month(c.request.Year, c.request.Month)
+	if err != nil {
+		return nil, err
+	}
+	return []calendar.MonthView{month}, nil
+}
+
+// View renders the calendar as laid out for width columns; a year wraps its
+// months into as many columns as fit. Errors, such as a year outside the
+// supported range, are rendered in place of the calendar.
+func (c CalendarComponent) View(width int) string {
+	views, err := c.Views()
+	if err != nil {
+		return err.Error()
+	}
+	body, err := layoutViews(views, width)
+	if err != nil {
+		return err.Error()
+	}
+	return body
+}
+
+// layoutViews lays views out for width columns, or fallbackWidth when width
+// is unknown.
+func layoutViews(views []calendar.MonthView, width int) (string, error) {
+	blocks, err := render.BuildBlocks(views)
+	if err != nil {
+		return "", err
+	}
+	if width <= 0 {
+		slog.Debug("terminal width unknown, using fallback", "width", fallbackWidth)
+		width = fallbackWidth
+	}
+	return render.Layout(blocks, width), nil
+}
+
+// todayRequest is the month view of now.
+func todayRequest(now time.Time) calendar.Request {
+	return calendar.Request{Year: now.Year(), Month: int(now.Month()), Mode: calendar.ModeMonth}
+}
@@ -1,20 +1,32 @@
 package tui
 
 import (
+	"fmt"
+	"log/slog"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
 
 	"github.com/lululau/lucal/internal/calendar"
 	"github.com/lululau/lucal/internal/render"
 )
 
+// DefaultJumpStep is the number of months ctrl+f/ctrl+b move by.
+const DefaultJumpStep = 6
+
 var (
-	noColorMode bool // Global flag to disable all color output
+	noColorMode bool              // Global flag to disable all color output
+	jumpStep    = DefaultJumpStep // Months moved by ctrl+f/ctrl+b
+	fiscalStart int               // First month of the year view; 0 or 1 is January
+	fillScreen  bool              // Month view shows as many months as fit
+	yearRange   [2]int            // First and last year navigation may reach; zeros for no limit
 )
 
 // SetNoColor sets the global no-color flag
@@ -22,15 +34,47 @@ func SetNoColor(disable bool) {
 	noColorMode = disable
 }
 
+// SetJumpStep sets how many months ctrl+f/ctrl+b move by; values below 1
+// restore DefaultJumpStep.
+func SetJumpStep(months int) {
+	if months < 1 {
+		months = DefaultJumpStep
+	}
+	jumpStep = months
+}
+
+// SetFiscalStart makes the year view show the twelve months from month
+// (2-12) instead of January through December; 0 or 1 restores calendar years.
+func SetFiscalStart(month int) {
+	fiscalStart = month
+}
+
+// SetFillScreen makes the month view show a run of consecutive months,
+// starting with the current one, that fills the terminal instead of a single
+// month.
+func SetFillScreen(enable bool) {
+	fillScreen = enable
+}
+
+// SetYearRange keeps navigation within the years from through to, e.g. a
+// --from/--to range paged with J/K; 0, 0 removes the limit.
+func SetYearRange(from, to int) {
+	yearRange = [2]int{from, to}
+}
+
 type inputMode int
 
 const (
 	inputNone inputMode = iota
 	inputYear
 	inputMonth
+	inputSearch
+	inputHighlight // typed live over the year view rather than on its own screen
 )
 
-// Run starts the interactive Bubble Tea UI.
+// Run starts the interactive Bubble Tea UI: a full-screen host for a
+// CalendarComponent that adds key bindings, input prompts, summaries and
+// the help line.
 func Run(svc *calendar.Service, req calendar.Request, holidayCacheValid bool) error {
 	if svc == nil {
 		svc = calendar.NewService()
@@ -43,14 +87,26 @@ func Run(svc *calendar.Service, req calendar.Request, holidayCacheValid bool) er
 
 type model struct {
 	svc               *calendar.Service
-	request           calendar.Request
+	cal               CalendarComponent
 	width             int
+	height            int
+	viewport          viewport.Model
 	inputMode         inputMode
 	input             textinput.Model
 	statusMsg         string
 	holidayCacheValid bool
+	showTerms         bool // the solar terms panel replaces the calendar
+	// highlight is the name typed after f; its matches in the year view are
+	// highlighted and every other day is dimmed.
+	highlight string
+	// history holds the requests left by jumps, most recent last, for
+	// ctrl+o to return to. Single steps with j/k/J/K are not recorded.
+	history []calendar.Request
 }
 
+// maxHistory caps how many jumps ctrl+o can go back through.
+const maxHistory = 50
+
 func newModel(svc *calendar.Service, req calendar.Request, holidayCacheValid bool) model {
 	ti := textinput.New()
 	ti.Placeholder = "数字"
@@ -58,20 +114,95 @@ func newModel(svc *calendar.Service, req calendar.Request, holidayCacheValid boo
 	ti.Prompt = "> "
 	return model{
 		svc:               svc,
-		request:           req,
+		cal:               NewCalendarComponent(svc, req),
+		viewport:          viewport.New(0, 0),
 		input:             ti,
 		holidayCacheValid: holidayCacheValid,
 	}
 }
 
+// maxSizeProbes bounds how often we query the terminal size ourselves when no
+// WindowSizeMsg shows up (seen under some multiplexers and SSH clients).
+const maxSizeProbes = 2
+
+// sizeProbeMsg carries the result of a direct terminal size query.
+type sizeProbeMsg struct {
+	attempt       int
+	width, height int
+	err           error
+}
+
+func probeSize(attempt int) tea.Cmd {
+	return func() tea.Msg {
+		w, h, err := term.GetSize(int(os.Stdout.Fd()))
+		return sizeProbeMsg{attempt: attempt, width: w, height: h, err: err}
+	}
+}
+
+// midnightMsg fires at the next local midnight so the today highlight moves
+// on when the UI is left open overnight.
+type midnightMsg struct{}
+
+// untilMidnight is the time left until the local midnight after now. It is
+// recomputed on every tick, so clock changes and DST shifts self-correct.
+func untilMidnight(now time.Time) time.Duration {
+	y, mo, d := now.Date()
+	return time.Date(y, mo, d+1, 0, 0, 0, 0, now.Location()).Sub(now)
+}
+
+func scheduleMidnight(now time.Time) tea.Cmd {
+	return tea.Tick(untilMidnight(now), func(time.Time) tea.Msg { return midnightMsg{} })
+}
+
 func (m model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(probeSize(1), scheduleMidnight(m.svc.Now()))
+}
+
+// applySize records the terminal dimensions.
+func (m *model) applySize(width, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = width
+	m.viewport.Height = height
 }
 
+// Update handles the message and then refreshes the viewport content so the
+// scroll position is always computed against what is currently rendered.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	// The highlight is render state, so it is only touched when a key
+	// changes it or leaves the year view, never while drawing.
+	if query := next.activeHighlight(); query != m.activeHighlight() {
+		render.SetSearchHighlight(query)
+	}
+	next.viewport.SetContent(next.content())
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.width = msg.Width
+		m.applySize(msg.Width, msg.Height)
+	case sizeProbeMsg:
+		if m.width > 0 {
+			// A real WindowSizeMsg already arrived; it is authoritative.
+			break
+		}
+		if msg.err == nil && msg.width > 0 {
+			slog.Debug("window size from terminal probe", "width", msg.width, "height", msg.height)
+			m.applySize(msg.width, msg.height)
+			break
+		}
+		slog.Debug("terminal size probe failed", "attempt", msg.attempt, "err", msg.err)
+		if msg.attempt < maxSizeProbes {
+			next := probeSize(msg.attempt + 1)
+			return m, tea.Tick(200*time.Millisecond, func(time.Time) tea.Msg { return next() })
+		}
+	case midnightMsg:
+		// Update re-renders after every message, which re-evaluates IsToday.
+		now := m.svc.Now()
+		slog.Debug("midnight tick", "now", now)
+		return m, scheduleMidnight(now)
 	case tea.KeyMsg:
 		if m.inputMode != inputNone {
 			return m.handleInputKey(msg)
@@ -79,50 +210,195 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
+		case "pgup":
+			m.viewport.PageUp()
+		case "pgdown":
+			m.viewport.PageDown()
 		case "k", "[":
-			m.request = m.request.PreviousMonth()
-			m.statusMsg = ""
+			m.navigate(NavigateMsg{Months: -m.monthStep()})
 		case "j", "]":
-			m.request = m.request.NextMonth()
-			m.statusMsg = ""
+			m.navigate(NavigateMsg{Months: m.monthStep()})
 		case "K", "{":
-			m.request = m.request.PreviousYear()
-			m.statusMsg = ""
+			m.navigate(NavigateMsg{Years: -1})
 		case "J", "}":
-			m.request = m.request.NextYear()
-			m.statusMsg = ""
+			m.navigate(NavigateMsg{Years: 1})
+		case "ctrl+b":
+			m.jump(NavigateMsg{Months: -jumpStep})
+		case "ctrl+f":
+			m.jump(NavigateMsg{Months: jumpStep})
+		case "ctrl+o", "backspace":
+			m.back()
 		case "y":
 			m.activateInput(inputYear, "")
 		case "m":
 			m.activateInput(inputMonth, "")
-		case ".":
-			now := time.Now()
-			m.request.Year = now.Year()
-			m.request.Month = int(now.Month())
-			m.request.Mode = calendar.ModeMonth
+		case "/":
+			m.activateInput(inputSearch, "")
+		case "f":
+			if m.cal.request.Mode != calendar.ModeYear {
+				m.statusMsg = "高亮匹配仅在年视图中可用"
+				break
+			}
+			m.activateInput(inputHighlight, "节日或节气名称")
+			m.input.SetValue(m.highlight)
+			m.input.CursorEnd()
+			m.statusMsg = m.highlightStatus()
+		case "t":
+			m.showTerms = !m.showTerms
 			m.statusMsg = ""
+		case "W":
+			next := nextWeekStart(m.svc.WeekStart())
+			m.svc.SetWeekStart(next)
+			m.statusMsg = "每周从周" + weekdayNames[next] + "开始"
+		case "esc":
+			m.showTerms = false
+			if m.highlight != "" {
+				m.highlight = ""
+				m.statusMsg = ""
+			}
+		case ".":
+			m.jump(TodayMsg{})
 		}
 	}
 	return m, nil
 }
 
+// navigate passes msg to the calendar and clears the status line.
+func (m *model) navigate(msg tea.Msg) {
+	m.cal, _ = m.cal.Update(msg)
+	m.statusMsg = ""
+	m.clampYear()
+}
+
+// clampYear moves the calendar back into SetYearRange, explaining why in
+// the status line.
+func (m *model) clampYear() {
+	from, to := yearRange[0], yearRange[1]
+	if from == 0 && to == 0 {
+		return
+	}
+	year := min(max(m.cal.request.Year, from), to)
+	if year == m.cal.request.Year {
+		return
+	}
+	m.cal.request.Year = year
+	m.statusMsg = fmt.Sprintf("只能浏览 %d-%d 年", from, to)
+}
+
+// jump is navigate for moves that ctrl+o can undo.
+func (m *model) jump(msg tea.Msg) {
+	from := m.cal.Request()
+	m.navigate(msg)
+	m.remember(from)
+}
+
+// remember records from in the history when the calendar has moved away
+// from it, dropping the oldest entry beyond maxHistory.
+func (m *model) remember(from calendar.Request) {
+	if m.cal.Request() == from {
+		return
+	}
+	m.history = append(m.history, from)
+	if len(m.history) > maxHistory {
+		m.history = m.history[len(m.history)-maxHistory:]
+	}
+}
+
+// back returns to the request before the latest jump.
+func (m *model) back() {
+	if len(m.history) == 0 {
+		m.statusMsg = "没有可以返回的位置"
+		return
+	}
+	last := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	m.navigate(GotoMsg{Request: last})
+}
+
+// weekStarts is the cycle W steps through.
+var weekStarts = []time.Weekday{time.Sunday, time.Monday, time.Saturday}
+
+var weekdayNames = []string{"日", "一", "二", "三", "四", "五", "六"}
+
+// nextWeekStart follows current in weekStarts; a week start outside the
+// cycle moves to Sunday.
+func nextWeekStart(current time.Weekday) time.Weekday {
+	for i, day := range weekStarts {
+		if day == current {
+			return weekStarts[(i+1)%len(weekStarts)]
+		}
+	}
+	return weekStarts[0]
+}
+
+// monthStep is how many months j/k move by: one, or a whole year in the
+// year view where every month is already on screen.
+func (m model) monthStep() int {
+	if m.cal.request.Mode == calendar.ModeYear {
+		return 12
+	}
+	return 1
+}
+
 func (m model) View() string {
-	if m.inputMode != inputNone {
+	if m.inputMode != inputNone && m.inputMode != inputHighlight {
 		return m.inputView()
 	}
+	// Until the first WindowSizeMsg arrives the viewport has no height.
+	if m.height <= 0 {
+		return m.content()
+	}
+	return m.viewport.View()
+}
 
-	body, err := m.renderCalendar()
+// content renders the full, unclipped screen body: calendar, help, status,
+// legend and warnings.
+func (m model) content() string {
+	var views []calendar.MonthView
+	var body string
+	var err error
+	if m.showTerms {
+		body, err = m.renderTerms()
+	} else if views, err = m.fetchViews(); err == nil {
+		body, err = layoutViews(views, m.width)
+	}
 	status := m.statusMsg
 	if err != nil {
 		status = err.Error()
 	}
 
-	help := render.HelpLine()
+	help := render.HelpLine(jumpStep, m.width)
 	sb := strings.Builder{}
+	if m.inputMode == inputHighlight {
+		// The prompt goes on top, where the viewport starts, so it stays in
+		// sight above a year taller than the terminal.
+		sb.WriteString(m.highlightPrompt(status))
+		sb.WriteString("\n\n")
+	}
 	sb.WriteString(body)
+	if summary := render.NotesSummary(views); summary != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(summary)
+	}
+	if summary := render.MarksSummary(views); summary != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(summary)
+	}
+	if summary := render.WorkdaySummary(views); summary != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(summary)
+	}
+	if summary := render.AlmanacSummary(views); summary != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(summary)
+	}
+	if summary := render.PentadSummary(views); summary != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(summary)
+	}
 	sb.WriteString("\n\n")
 	sb.WriteString(help)
-	if status != "" {
+	if status != "" && m.inputMode != inputHighlight {
 		sb.WriteString("\n")
 		if noColorMode {
 			sb.WriteString(status)
@@ -131,15 +407,14 @@ func (m model) View() string {
 		}
 	}
 
-	// Show color legend if holiday data is available
-	if m.svc.HasHolidayData() {
+	if legend := render.ColorLegend(views, m.width); legend != "" {
 		sb.WriteString("\n")
-		sb.WriteString(render.ColorLegend())
+		sb.WriteString(legend)
 	}
 
-	if !m.holidayCacheValid {
+	if hint := render.UpdateHint(m.holidayCacheValid); hint != "" {
 		sb.WriteString("\n")
-		warningMsg := "\n尚未下载节假日数据或节假日数据超过 6 个月未更新，运行  lucal -u 获取最新数据"
+		warningMsg := "\n" + hint
 		if noColorMode {
 			sb.WriteString(warningMsg)
 		} else {
@@ -150,31 +425,58 @@ func (m model) View() string {
 	return sb.String()
 }
 
-func (m model) renderCalendar() (string, error) {
-	views, err := m.fetchViews()
-	if err != nil {
-		return "", err
-	}
-	blocks, err := render.BuildBlocks(views)
+// renderTerms lists the solar terms of the current year, pointing at the
+// next one from today.
+func (m model) renderTerms() (string, error) {
+	terms, err := m.svc.SolarTerms(m.cal.request.Year)
 	if err != nil {
 		return "", err
 	}
-	width := m.width
-	if width <= 0 {
-		width = 100
-	}
-	return render.Layout(blocks, width), nil
+	return render.SolarTermsPanel(m.cal.request.Year, terms, m.svc.Now()), nil
 }
 
+// fetchViews returns the months on screen: the calendar's views, grown to
+// fill the terminal in fill-screen mode.
 func (m model) fetchViews() ([]calendar.MonthView, error) {
-	month, err := m.svc.Month(m.request.Year, m.request.Month)
+	cal := m.cal
+	if fillScreen && m.height > 0 {
+		cal.months = m.fillCount()
+	}
+	return cal.Views()
+}
+
+// chromeLines is the height kept free below the calendar for the help line,
+// status, legend and update hint.
+const chromeLines = 6
+
+// fillCount is how many months fit the terminal in fill-screen mode: as many
+// rows of Layout's columns as the height allows, at least one month and at
+// most a year.
+func (m model) fillCount() int {
+	// Two months are padded to six weeks like the final run, so their blocks
+	// have its height.
+	probe, err := m.svc.Months(m.cal.request.Year, m.cal.request.Month, 2)
+	if err != nil {
+		return 1
+	}
+	blocks, err := render.BuildBlocks(probe)
 	if err != nil {
-		return nil, err
+		return 1
+	}
+	year := make([]render.MonthBlock, 12)
+	for i := range year {
+		year[i] = blocks[0]
 	}
-	return []calendar.MonthView{month}, nil
+	cols := render.LayoutColumns(year, max(m.width, 1))
+	// Rows of blocks are separated by one blank line.
+	rows := (m.height - chromeLines + 1) / (blocks[0].Height + 1)
+	return max(1, min(cols*max(rows, 1), 12))
 }
 
-func (m model) handleInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m model) handleInputKey(msg tea.KeyMsg) (model, tea.Cmd) {
+	if m.inputMode == inputHighlight {
+		return m.handleHighlightKey(msg)
+	}
 	switch msg.Type {
 	case tea.KeyEsc:
 		m.inputMode = inputNone
@@ -192,6 +494,75 @@ func (m model) handleInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// handleHighlightKey edits the highlight query, re-highlighting the year on
+// every keystroke. Enter keeps the highlight and Esc clears it.
+func (m model) handleHighlightKey(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.highlight = ""
+		m.statusMsg = ""
+	case tea.KeyEnter:
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		m.highlight = strings.TrimSpace(m.input.Value())
+		m.statusMsg = m.highlightStatus()
+		return m, cmd
+	}
+	m.inputMode = inputNone
+	m.input.Blur()
+	return m, nil
+}
+
+// activeHighlight is the query the calendar is highlighted with: the
+// highlight while the year view is shown, "" otherwise.
+func (m model) activeHighlight() string {
+	if m.showTerms || m.cal.request.Mode != calendar.ModeYear {
+		return ""
+	}
+	return m.highlight
+}
+
+// highlightStatus counts the in-month days on screen matching the highlight.
+func (m model) highlightStatus() string {
+	query := m.activeHighlight()
+	if query == "" {
+		return ""
+	}
+	views, err := m.fetchViews()
+	if err != nil {
+		return err.Error()
+	}
+	count := 0
+	for _, view := range views {
+		for _, day := range view.Days() {
+			if day.Matches(query) {
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return "没有名称包含 " + query + " 的节假日或节气"
+	}
+	return fmt.Sprintf("%d 天匹配 %s", count, query)
+}
+
+// highlightPrompt is the input line of the highlight query followed by its
+// status.
+func (m model) highlightPrompt(status string) string {
+	label := "高亮节假日或节气 (回车保留 / Esc 清除)"
+	if !noColorMode {
+		label = lipgloss.NewStyle().Bold(true).Render(label)
+	}
+	line := label + "  " + m.input.View()
+	if status != "" {
+		line += "  " + status
+	}
+	return line
+}
+
 func (m *model) activateInput(mode inputMode, placeholder string) {
 	m.inputMode = mode
 	m.input.SetValue("")
@@ -205,8 +576,13 @@ func (m *model) applyInput() {
 	value := strings.TrimSpace(m.input.Value())
 	if value == "" {
 		m.statusMsg = "请输入数字"
+		if m.inputMode == inputSearch {
+			m.statusMsg = "请输入名称"
+		}
 		return
 	}
+	from := m.cal.request
+	status := ""
 	switch m.inputMode {
 	case inputYear:
 		fields := strings.Fields(value)
@@ -219,16 +595,16 @@ func (m *model) applyInput() {
 			m.statusMsg = "无效的年份"
 			return
 		}
-		m.request.Year = year
+		m.cal.request.Year = year
 		if len(fields) == 2 {
 			month, err := strconv.Atoi(fields[1])
 			if err != nil || month < 1 || month > 12 {
 				m.statusMsg = "月份需在 1-12 之间"
 				return
 			}
-			m.request.Month = month
+			m.cal.request.Month = month
+			m.cal.request.Mode = calendar.ModeMonth
 		}
-		m.request.Mode = calendar.ModeMonth
 	case inputMonth:
 		num, err := strconv.Atoi(value)
 		if err != nil {
@@ -239,11 +615,24 @@ func (m *model) applyInput() {
 			m.statusMsg = "月份需在 1-12 之间"
 			return
 		}
-		m.request.Month = num
-		m.request.Mode = calendar.ModeMonth
+		m.cal.request.Month = num
+		m.cal.request.Mode = calendar.ModeMonth
+	case inputSearch:
+		from := time.Date(m.cal.request.Year, time.Month(m.cal.request.Month), 1, 0, 0, 0, 0, m.svc.Location())
+		result, ok := m.svc.FindNext(value, from)
+		if !ok {
+			status = "未找到 " + value
+			break
+		}
+		m.cal.request.Year = result.Date.Year()
+		m.cal.request.Month = int(result.Date.Month())
+		m.cal.request.Mode = calendar.ModeMonth
+		status = result.Name + "：" + result.Date.Format("2006-01-02")
 	}
-	m.request = m.request.Normalize()
-	m.statusMsg = ""
+	m.cal.request = m.cal.request.Normalize()
+	m.statusMsg = status
+	m.clampYear()
+	m.remember(from)
 	m.inputMode = inputNone
 	m.input.Blur()
 }
@@ -255,6 +644,8 @@ func (m model) inputView() string {
 		label = "输入年份 (回车确认 / Esc 取消)"
 	case inputMonth:
 		label = "输入月份 1-12 (回车确认 / Esc 取消)"
+	case inputSearch:
+		label = "搜索节假日或节气，从当前月份向后查找 (回车确认 / Esc 取消)"
 	default:
 		return ""
 	}
@@ -0,0 +1,268 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lululau/lucal/internal/calendar"
+)
+
+func TestUntilMidnight(t *testing.T) {
+	now := time.Date(2025, 11, 11, 23, 59, 30, 0, time.Local)
+	if got := untilMidnight(now); got != 30*time.Second {
+		t.Fatalf("untilMidnight=%v want 30s", got)
+	}
+	now = time.Date(2025, 12, 31, 0, 0, 0, 0, time.Local)
+	if got := untilMidnight(now); got != 24*time.Hour {
+		t.Fatalf("untilMidnight at midnight=%v want 24h", got)
+	}
+}
+
+func TestMidnightTickMovesToday(t *testing.T) {
+	now := time.Date(2025, 11, 11, 23, 59, 30, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	m := newModel(svc, calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeMonth}, true)
+
+	next, _ := m.Update(midnightMsg{})
+	before := next.(model).content()
+
+	now = now.Add(time.Minute)
+	next, cmd := next.Update(midnightMsg{})
+	if cmd == nil {
+		t.Fatal("expected the midnight tick to be rescheduled")
+	}
+	if after := next.(model).content(); after == before {
+		t.Fatalf("expected the today highlight to move after midnight:\n%s", after)
+	}
+}
+
+func TestYearModeShowsWholeYear(t *testing.T) {
+	svc := calendar.NewService()
+	m := newModel(svc, calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeYear}, true)
+	m.applySize(200, 80)
+
+	content := m.content()
+	for _, title := range []string{"2025 年 1 月", "2025 年 12 月"} {
+		if !strings.Contains(content, title) {
+			t.Fatalf("expected %s in the year view:\n%s", title, content)
+		}
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if req := next.(model).cal.Request(); req.Year != 2026 || req.Mode != calendar.ModeYear {
+		t.Fatalf("expected j to move to the next year in year mode, got %+v", req)
+	}
+}
+
+func TestYearRangeLimitsNavigation(t *testing.T) {
+	SetYearRange(2023, 2025)
+	defer SetYearRange(0, 0)
+	var m tea.Model = newModel(calendar.NewService(), calendar.Request{Year: 2023, Month: 1, Mode: calendar.ModeYear}, true)
+	press := func(key string) calendar.Request {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return m.(model).cal.Request()
+	}
+	if req := press("K"); req.Year != 2023 || m.(model).statusMsg == "" {
+		t.Fatalf("expected K to stop at 2023 with a status, got %+v %q", req, m.(model).statusMsg)
+	}
+	for _, want := range []int{2024, 2025, 2025} {
+		if req := press("J"); req.Year != want {
+			t.Fatalf("J: got %d want %d", req.Year, want)
+		}
+	}
+}
+
+func TestBackReturnsBeforeJumps(t *testing.T) {
+	now := time.Date(2025, 11, 11, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	var m tea.Model = newModel(svc, calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeMonth}, true)
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			m, _ = m.Update(key)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	request := func() calendar.Request { return m.(model).cal.Request() }
+
+	press(runes("y"), runes("2030 5"), tea.KeyMsg{Type: tea.KeyEnter}) // jump to 2030-05
+	press(runes("j"))                                                  // single step, not recorded
+	press(tea.KeyMsg{Type: tea.KeyCtrlF})                              // jump 6 months ahead
+	if got := request(); got.Year != 2030 || got.Month != 12 {
+		t.Fatalf("expected 2030-12 after the jumps, got %+v", got)
+	}
+	for _, want := range []calendar.Request{{Year: 2030, Month: 6}, {Year: 2025, Month: 11}} {
+		press(tea.KeyMsg{Type: tea.KeyCtrlO})
+		if got := request(); got != want {
+			t.Fatalf("ctrl+o: got %+v want %+v", got, want)
+		}
+	}
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	if got := request(); got.Year != 2025 || got.Month != 11 || m.(model).statusMsg == "" {
+		t.Fatalf("expected to stay put with a status message on an empty history, got %+v %q", got, m.(model).statusMsg)
+	}
+
+	for i := 0; i < maxHistory+10; i++ {
+		press(tea.KeyMsg{Type: tea.KeyCtrlF})
+	}
+	if got := len(m.(model).history); got != maxHistory {
+		t.Fatalf("history length=%d want %d", got, maxHistory)
+	}
+}
+
+func TestHighlightYearAsYouType(t *testing.T) {
+	const searchColor = "\x1b[38;2;234;179;8m"
+	svc := calendar.NewService()
+	var m tea.Model = newModel(svc, calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeYear}, true)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 200, Height: 80})
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			m, _ = m.Update(key)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	press(runes("f"), runes("冬"))
+	if got := m.(model).statusMsg; got != "2 天匹配 冬" {
+		t.Fatalf("expected 立冬 and 冬至 to match, got status %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "2 天匹配 冬") || !strings.Contains(view, "2025 年 1 月") {
+		t.Fatalf("expected the prompt above the year:\n%s", view)
+	}
+	if content := m.(model).content(); !strings.Contains(content, searchColor) {
+		t.Fatal("expected the matches highlighted")
+	}
+
+	press(runes("x"))
+	if got := m.(model).statusMsg; !strings.Contains(got, "没有") {
+		t.Fatalf("expected a no-match note, got %q", got)
+	}
+	if content := m.(model).content(); strings.Contains(content, searchColor) {
+		t.Fatal("expected no day highlighted without matches")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.(model); got.inputMode != inputNone || got.highlight != "冬" {
+		t.Fatalf("expected enter to keep the highlight, got mode %v highlight %q", got.inputMode, got.highlight)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if content := m.(model).content(); m.(model).highlight != "" || strings.Contains(content, searchColor) {
+		t.Fatal("expected esc to restore the normal colors")
+	}
+	year := NewCalendarComponent(svc, calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeYear})
+	if strings.Contains(year.View(200), searchColor) {
+		t.Fatal("expected the highlight not to leak into other calendars")
+	}
+
+	month := newModel(svc, calendar.Request{Year: 2025, Month: 1, Mode: calendar.ModeMonth}, true)
+	if next, _ := month.Update(runes("f")); next.(model).inputMode != inputNone || next.(model).statusMsg == "" {
+		t.Fatal("expected f to explain that highlighting needs the year view")
+	}
+}
+
+func TestCalendarComponent(t *testing.T) {
+	now := time.Date(2025, 11, 11, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	c := NewCalendarComponent(svc, calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeMonth})
+	if view := c.View(60); !strings.Contains(view, "2025 年 11 月") {
+		t.Fatalf("expected November 2025 in the component view:\n%s", view)
+	}
+
+	steps := []struct {
+		msg  tea.Msg
+		want calendar.Request
+	}{
+		{NavigateMsg{Months: 2}, calendar.Request{Year: 2026, Month: 1}},
+		{NavigateMsg{Years: -1, Months: -1}, calendar.Request{Year: 2024, Month: 12}},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}, calendar.Request{Year: 2024, Month: 12}}, // keys belong to the host
+		{GotoMsg{calendar.Request{Year: 2030, Month: 14, Mode: calendar.ModeYear}}, calendar.Request{Year: 2031, Month: 2, Mode: calendar.ModeYear}},
+		{TodayMsg{}, calendar.Request{Year: 2025, Month: 11}},
+	}
+	for _, step := range steps {
+		var cmd tea.Cmd
+		if c, cmd = c.Update(step.msg); cmd != nil {
+			t.Fatalf("%T: expected no command", step.msg)
+		}
+		if got := c.Request(); got != step.want {
+			t.Fatalf("%T: Request()=%+v want %+v", step.msg, got, step.want)
+		}
+	}
+
+	c, _ = c.Update(GotoMsg{calendar.Request{Year: 3001, Month: 1}})
+	if view := c.View(60); !strings.Contains(view, "3001") {
+		t.Fatalf("expected the range error in place of the calendar, got %q", view)
+	}
+}
+
+func TestSolarTermsPanel(t *testing.T) {
+	now := time.Date(2025, 12, 10, 9, 0, 0, 0, time.Local)
+	svc := calendar.NewService(calendar.WithNow(func() time.Time { return now }))
+	m := newModel(svc, calendar.Request{Year: 2025, Month: 12, Mode: calendar.ModeMonth}, true)
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	content := next.(model).content()
+	if !strings.Contains(content, "2025 年二十四节气") || !strings.Contains(content, "▶ 冬至") {
+		t.Fatalf("expected the 2025 solar terms with 冬至 next:\n%s", content)
+	}
+
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	if content := next.(model).content(); !strings.Contains(content, "2026 年二十四节气") {
+		t.Fatalf("expected J to refresh the panel for 2026:\n%s", content)
+	}
+
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if content := next.(model).content(); strings.Contains(content, "二十四节气") {
+		t.Fatalf("expected Esc to close the panel:\n%s", content)
+	}
+}
+
+func TestFillScreen(t *testing.T) {
+	SetFillScreen(true)
+	defer SetFillScreen(false)
+	svc := calendar.NewService()
+	m := newModel(svc, calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeMonth}, true)
+
+	m.applySize(40, 30)
+	if got := m.fillCount(); got != 1 {
+		t.Fatalf("expected a single month in a 40x30 terminal, got %d", got)
+	}
+	m.applySize(40, 60)
+	if got := m.fillCount(); got != 2 {
+		t.Fatalf("expected two stacked months in a 40x60 terminal, got %d", got)
+	}
+
+	content := m.content()
+	for _, title := range []string{"2025 年 11 月", "2025 年 12 月"} {
+		if !strings.Contains(content, title) {
+			t.Fatalf("expected %s in the filled view:\n%s", title, content)
+		}
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	content = next.(model).content()
+	if strings.Contains(content, "2025 年 11 月") || !strings.Contains(content, "2026 年 1 月") {
+		t.Fatalf("expected j to scroll the run by one month:\n%s", content)
+	}
+}
+
+func TestCycleWeekStart(t *testing.T) {
+	svc := calendar.NewService()
+	var m tea.Model = newModel(svc, calendar.Request{Year: 2025, Month: 11, Mode: calendar.ModeMonth}, true)
+	for _, want := range []time.Weekday{time.Monday, time.Saturday, time.Sunday} {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+		if got := svc.WeekStart(); got != want {
+			t.Fatalf("expected W to switch the week start to %v, got %v", want, got)
+		}
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	content := m.(model).content()
+	if !strings.Contains(content, "一      二      三      四      五      六      日") {
+		t.Fatalf("expected a Monday-first header:\n%s", content)
+	}
+	if !strings.Contains(content, "每周从周一开始") {
+		t.Fatalf("expected a status line naming the new week start:\n%s", content)
+	}
+}
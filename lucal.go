@@ -0,0 +1,159 @@
+// Package lucal is the public API of lucal for use as a library. It exposes
+// the calendar service that backs the command, loaders for the holiday data
+// it highlights and CalendarComponent, the calendar grid of the interactive
+// UI as a Bubble Tea widget; everything else stays internal and may change.
+//
+//	svc := lucal.NewService(lucal.WithWeekStart(time.Monday))
+//	view, err := svc.Month(2025, 10)
+package lucal
+
+import (
+	"time"
+
+	"github.com/lululau/lucal/internal/almanac"
+	"github.com/lululau/lucal/internal/calendar"
+	"github.com/lululau/lucal/internal/holidays"
+	"github.com/lululau/lucal/internal/tui"
+)
+
+// Supported Gregorian year range.
+const (
+	MinSupportedYear = calendar.MinSupportedYear
+	MaxSupportedYear = calendar.MaxSupportedYear
+)
+
+// View modes of a Request.
+const (
+	ModeMonth = calendar.ModeMonth
+	ModeYear  = calendar.ModeYear
+)
+
+type (
+	// Service builds month and year views. See calendar.Service.
+	Service = calendar.Service
+	// Option configures a Service.
+	Option = calendar.Option
+	// Request is a year/month/mode to render.
+	Request = calendar.Request
+	// ViewMode selects a month or a whole year.
+	ViewMode = calendar.ViewMode
+	// MonthView is a month laid out into weeks.
+	MonthView = calendar.MonthView
+	// Day is a Gregorian day with its lunar, solar-term and holiday data.
+	Day = calendar.Day
+	// SearchResult is a match returned by Service.FindNext.
+	SearchResult = calendar.SearchResult
+	// LunarMonthStart is a 初一 returned by Service.LunarMonthStarts.
+	LunarMonthStart = calendar.LunarMonthStart
+	// HolidayEntry is one day of the holiday data.
+	HolidayEntry = holidays.HolidayEntry
+	// HolidayInfo describes the holiday or adjusted workday a Day falls on.
+	HolidayInfo = holidays.HolidayInfo
+	// Holidays is holiday data keyed by year and then by "MM-DD".
+	Holidays = map[string]map[string]*holidays.HolidayEntry
+	// AlmanacEntry lists a day's 宜 and 忌.
+	AlmanacEntry = almanac.Entry
+)
+
+var (
+	// ErrYearOutOfRange reports a year outside the supported range.
+	ErrYearOutOfRange = calendar.ErrYearOutOfRange
+	// ErrInvalidMonth reports a month outside 1..12.
+	ErrInvalidMonth = calendar.ErrInvalidMonth
+)
+
+type (
+	// CalendarComponent renders a month or year into a string for a host
+	// Bubble Tea program. See tui.CalendarComponent for the message contract.
+	CalendarComponent = tui.CalendarComponent
+	// NavigateMsg moves a CalendarComponent by years and months.
+	NavigateMsg = tui.NavigateMsg
+	// GotoMsg makes a CalendarComponent show a Request.
+	GotoMsg = tui.GotoMsg
+	// TodayMsg makes a CalendarComponent show the current month.
+	TodayMsg = tui.TodayMsg
+)
+
+// NewCalendarComponent returns a CalendarComponent showing req with the data
+// of svc.
+func NewCalendarComponent(svc *Service, req Request) CalendarComponent {
+	return tui.NewCalendarComponent(svc, req)
+}
+
+type (
+	// YearRangeError is the errors.As target for ErrYearOutOfRange.
+	YearRangeError = calendar.YearRangeError
+	// MonthError is the errors.As target for ErrInvalidMonth.
+	MonthError = calendar.MonthError
+	// HolidayParseError reports holiday data that could not be decoded.
+	HolidayParseError = holidays.HolidayParseError
+)
+
+// NewService constructs a Service.
+func NewService(opts ...Option) *Service {
+	return calendar.NewService(opts...)
+}
+
+// WithNow overrides the clock used to flag today.
+func WithNow(now func() time.Time) Option {
+	return calendar.WithNow(now)
+}
+
+// WithHolidays attaches holiday data, e.g. from LoadHolidays.
+func WithHolidays(data Holidays) Option {
+	return calendar.WithHolidays(data)
+}
+
+// WithNotes attaches personal notes keyed by YYYY-MM-DD.
+func WithNotes(data map[string]string) Option {
+	return calendar.WithNotes(data)
+}
+
+// WithAlmanac attaches 宜/忌 data keyed by Day.LunarDateStringWithYear.
+func WithAlmanac(data map[string]AlmanacEntry) Option {
+	return calendar.WithAlmanac(data)
+}
+
+// WithWeekStart sets the first column of every week. The default is Sunday.
+func WithWeekStart(day time.Weekday) Option {
+	return calendar.WithWeekStart(day)
+}
+
+// WithWeekend sets the rest days of the week used for weekend highlighting
+// and Service.IsWorkingDay. The default is Saturday and Sunday.
+func WithWeekend(days ...time.Weekday) Option {
+	return calendar.WithWeekend(days...)
+}
+
+// Age returns the Gregorian age (周岁) and nominal lunar age (虚岁) of
+// someone born on birth, as of asOf.
+func Age(birth, asOf time.Time) (solarAge, nominalAge int) {
+	return calendar.Age(birth, asOf)
+}
+
+// LunarNewYear returns the date of 正月初一 in the Gregorian year, e.g.
+// 2025-01-29 for 2025.
+func LunarNewYear(year int) (time.Time, error) {
+	return calendar.LunarNewYear(year)
+}
+
+// WesternZodiac returns the Western star sign (星座) of t's date, e.g. 狮子座.
+func WesternZodiac(t time.Time) string {
+	return calendar.WesternZodiac(t)
+}
+
+// LoadHolidays reads holiday data in the format of holidays.json.
+func LoadHolidays(path string) (Holidays, error) {
+	return holidays.LoadFromFile(path)
+}
+
+// LoadCachedHolidays reads the holiday data downloaded by lucal -u.
+func LoadCachedHolidays() (Holidays, error) {
+	return holidays.LoadFromCache()
+}
+
+// IsWorkingDay reports whether t is a working day under data, taking 调休
+// into account; reason explains the determination.
+func IsWorkingDay(data Holidays, t time.Time) (working bool, reason string) {
+	return holidays.IsWorkingDay(data, t)
+}
@@ -0,0 +1,22 @@
+package lucal_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lululau/lucal"
+)
+
+func ExampleNewService() {
+	svc := lucal.NewService(lucal.WithWeekStart(time.Monday))
+	view, err := svc.Month(2025, 10)
+	if err != nil {
+		panic(err)
+	}
+	day := view.Days()[5]
+	fmt.Println(day.Date.Format("2006-01-02"), day.LunarDateStringWithYear())
+	fmt.Println(view.Weeks[0][0].Date.Weekday())
+	// Output:
+	// 2025-10-06 乙巳年八月十五
+	// Monday
+}